@@ -6,6 +6,7 @@ require (
 	github.com/rs/zerolog v1.34.0
 	github.com/spf13/cobra v1.10.1
 	github.com/xlab/treeprint v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -2,15 +2,22 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"slices"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/cupsadarius/gcp_resource_cleaner/models"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/cli"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/plan"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/report"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/snapshot"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/version"
 )
 
@@ -20,14 +27,52 @@ const longDesc = `gcp_resource_cleaner is a CLI tool for cleaning up GCP resourc
 
 Since GCP prevents you from deleting resources that are in use,
 this tool recursively traverses the GCP resource tree and deletes
-all resources from bottom up given a starting folder id.`
+all resources from bottom up given a starting folder or organization id.`
 
-var rootFolderId string
+var rootFolderIds []string
+var rootFolderIdsFile string
+var rootFolderPaths []string
+var organizationId string
 var dryRun bool
 var logLevel string
 var logFormat string
 var enableConcurrency bool
 var concurrecyLimit int
+var backend string
+var apiEndpoint string
+var outputFile string
+var fromSnapshot string
+var planFile string
+var matchPatterns []string
+var excludePatterns []string
+var includeLabels []string
+var excludeLabels []string
+var olderThan string
+var createdBefore string
+var maxDepth int
+var displayDepth int
+var removeLiens bool
+var showLiens bool
+var reportFile string
+var backupDir string
+var diffFrom string
+var diffTo string
+var diffFormat string
+var fromReport string
+var journalFile string
+var retryMaxAttempts int
+var retryBaseDelay time.Duration
+var retryMaxDelay time.Duration
+var retryJitter float64
+var resumeJournal string
+var readQPS float64
+var discoveryWorkers int
+var deleteWorkers int
+var failFast bool
+var writeQPS float64
+
+// accessTokenEnv is read by the api backend before falling back to gcloud for a token
+const accessTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
 
 // Add this function to app.go
 func createExecutor() gcp.CommandExecutor {
@@ -44,28 +89,124 @@ func createExecutor() gcp.CommandExecutor {
 	}
 }
 
+// createRateLimiter returns the limiter of a run from --read-qps and --write-qps. Handlers create it once and hand it
+// to the client and to the summary of the run, which reports its wait time.
+func createRateLimiter() *gcp.RateLimiter {
+	log := logger.New(appID, "createRateLimiter")
+	log.DebugWithExtra("Creating rate limiter", map[string]any{
+		"readQPS":  readQPS,
+		"writeQPS": writeQPS,
+	})
+
+	return gcp.NewRateLimiter(readQPS, writeQPS)
+}
+
+// createClient returns the client of the selected backend, retrying list and delete calls that fail with a transient error.
+// Every call of the client waits for limiter.
+func createClient(limiter *gcp.RateLimiter) gcp.ResourceClient {
+	log := logger.New(appID, "createClient")
+	policy := gcp.RetryPolicy{MaxAttempts: retryMaxAttempts, BaseDelay: retryBaseDelay, MaxDelay: retryMaxDelay, Jitter: retryJitter}
+	log.DebugWithExtra("Creating retrying client", map[string]any{
+		"maxAttempts": policy.MaxAttempts,
+		"baseDelay":   policy.BaseDelay.String(),
+		"maxDelay":    policy.MaxDelay.String(),
+		"jitter":      policy.Jitter,
+	})
+
+	return gcp.NewRetryingClient(createBackendClient(limiter), policy)
+}
+
+func createBackendClient(limiter *gcp.RateLimiter) gcp.ResourceClient {
+	log := logger.New(appID, "createBackendClient")
+	executor := gcp.NewRateLimitedExecutor(createExecutor(), limiter)
+
+	if strings.ToLower(backend) != gcp.BackendAPI {
+		log.Debug("Creating gcloud client")
+		return gcp.NewGCloudClient(executor)
+	}
+
+	token := gcp.GCloudToken(executor)
+	if value := os.Getenv(accessTokenEnv); value != "" {
+		token = gcp.StaticToken(value)
+	}
+
+	maxConcurrent := 1
+	if enableConcurrency {
+		maxConcurrent = concurrecyLimit
+	}
+	log.DebugWithExtra("Creating Resource Manager API client", map[string]any{
+		"endpoint":      apiEndpoint,
+		"maxConcurrent": maxConcurrent,
+	})
+
+	return gcp.NewRESTClient(apiEndpoint, token, maxConcurrent).WithRateLimiter(limiter)
+}
+
 func Run(ctx context.Context) error {
 	cli.Init(appID, shortDesc, longDesc)
 	_ = cli.AddCommand("version", "Get the application version and Git commit SHA", logVersionDetails)
 	_ = cli.AddCommand("check-health", "Check if we have the required tools installed", checkHealth)
 	_ = cli.AddCommand("delete", "Delete all resources from a given folder", deleteResources)
 	_ = cli.AddCommand("print", "Print the resource tree", printTree)
-	cli.AssignStringFlag(&rootFolderId, "folder-id", "", "Root folder id to start from")
+	_ = cli.AddCommand("plan", "Write the ordered list of resources to delete to a plan file for review", planResources)
+	_ = cli.AddCommand("pending", "List the projects pending deletion with their scheduled purge date", listPending)
+	_ = cli.AddCommand("diff", "Show what changed between a snapshot and another snapshot or the live tree", diffResources)
+	_ = cli.AddCommand("apply", "Delete exactly the resources listed in a plan file, refusing if the tree has drifted", applyPlan)
+	_ = cli.AddCommand("restore", "Undelete the resources removed by a past run, as recorded in its report", restoreResources)
+	cli.AssignStringSliceFlag(&rootFolderIds, "folder-id", nil, "Root folder id to start from, can be repeated")
+	cli.AssignStringFlag(&rootFolderIdsFile, "folder-ids-file", "", "File with one root folder id per line")
+	cli.AssignStringArrayFlag(&rootFolderPaths, "folder-path", nil, "Display name path of a root folder, e.g. \"Acme Org/Engineering/Sandboxes\", can be repeated")
+	cli.AssignStringFlag(&organizationId, "organization-id", "", "Organization id to start from, the organization itself is never deleted")
 	cli.AssignStringFlag(&logLevel, "log-level", "info", "Log level (trace, debug, info, warn, error, fatal, panic)")
 	cli.AssignStringFlag(&logFormat, "log-format", "pretty", "Log format (pretty, json)")
 	cli.AssignBoolFlag(&dryRun, "dry-run", false, "Dry run mode")
 	cli.AssignBoolFlag(&enableConcurrency, "concurrency", false, "Enable concurrency")
 	cli.AssignIntFlag(&concurrecyLimit, "concurrency-limit", 5, "Concurrency limit")
+	cli.AssignStringFlag(&backend, "backend", gcp.BackendGCloud, "Backend used to talk to GCP (gcloud, api)")
+	cli.AssignStringFlag(&apiEndpoint, "api-endpoint", gcp.DefaultResourceManagerURL, "Resource Manager endpoint used by the api backend")
+	cli.AssignStringFlag(&outputFile, "output-file", "", "Write a snapshot of the discovered tree to this file (.json, .yaml or .yml)")
+	cli.AssignStringFlag(&fromSnapshot, "from-snapshot", "", "Load the tree from a snapshot file instead of discovering it")
+	cli.AssignStringArrayFlag(&matchPatterns, "match", nil, "Only delete folders and projects whose name or id matches a glob, or a regex prefixed with re:, a matching folder selects its subtree, can be repeated")
+	cli.AssignStringArrayFlag(&excludePatterns, "exclude", nil, "Never delete folders and projects whose name or id matches a glob, or a regex prefixed with re:, a matching folder prunes its subtree, can be repeated")
+	cli.AssignStringArrayFlag(&includeLabels, "include-label", nil, "Only delete projects with matching labels, e.g. env=sandbox, keep!=true, team or !keep, can be repeated")
+	cli.AssignStringArrayFlag(&excludeLabels, "exclude-label", nil, "Never delete projects with matching labels, e.g. keep=true, can be repeated")
+	cli.AssignStringFlag(&olderThan, "older-than", "", "Only delete folders and projects created longer ago than this age, e.g. 30d, 2w or 36h")
+	cli.AssignStringFlag(&createdBefore, "created-before", "", "Only delete folders and projects created before this date, e.g. 2024-01-31 or an RFC 3339 timestamp")
+	cli.AssignIntFlag(&maxDepth, "max-depth", 0, "Stop discovery this many folder levels below each root, deeper folders are marked truncated and never deleted, 0 discovers everything")
+	cli.AssignIntFlag(&displayDepth, "display-depth", 0, "Collapse folders this many levels below each root into a summary when printing, 0 prints everything")
+	cli.AssignBoolFlag(&showLiens, "show-liens", false, "List the liens of every project when printing, delete and plan always list them for the projects they delete")
+	cli.AssignBoolFlag(&removeLiens, "remove-liens", false, "Remove the liens of each project right before deleting it")
+	cli.AssignStringFlag(&reportFile, "report-file", "", "Write the record of the run, including removed liens, to this file")
+	cli.AssignStringFlag(&backupDir, "backup-dir", "", "Back up the IAM policy, labels, enabled services, billing and parent of each project to this directory before deleting it")
+	cli.AssignStringFlag(&diffFrom, "diff-from", "", "Snapshot file holding the earlier tree to diff from")
+	cli.AssignStringFlag(&diffTo, "diff-to", "", "Snapshot file holding the later tree to diff to, the live tree of the given roots when empty")
+	cli.AssignStringFlag(&diffFormat, "diff-format", "tree", "Diff output format (tree, json)")
+	cli.AssignIntFlag(&retryMaxAttempts, "retry-max-attempts", gcp.DefaultRetryPolicy.MaxAttempts, "Attempts of a list or delete call that fails with a transient error such as 429 or 503, 1 disables retries")
+	cli.AssignDurationFlag(&retryBaseDelay, "retry-base-delay", gcp.DefaultRetryPolicy.BaseDelay, "Wait before the first retry, doubled for every further retry")
+	cli.AssignDurationFlag(&retryMaxDelay, "retry-max-delay", gcp.DefaultRetryPolicy.MaxDelay, "Longest wait between two attempts, 0 is unlimited")
+	cli.AssignFloat64Flag(&retryJitter, "retry-jitter", gcp.DefaultRetryPolicy.Jitter, "Fraction of each wait that is randomized, between 0 and 1")
+	cli.AssignIntFlag(&discoveryWorkers, "discovery-workers", 0, "Folders listed in parallel during discovery, 0 uses --concurrency-limit with --concurrency and 1 without")
+	cli.AssignIntFlag(&deleteWorkers, "delete-workers", 0, "Folders and projects deleted in parallel, 0 uses --concurrency-limit with --concurrency and 1 without")
+	cli.AssignBoolFlag(&failFast, "fail-fast", false, "Stop starting deletions after the first failure, the remaining entries are skipped")
+	cli.AssignFloat64Flag(&readQPS, "read-qps", 0, "Maximum list and describe calls per second, 0 is unlimited")
+	cli.AssignFloat64Flag(&writeQPS, "write-qps", 0, "Maximum delete and undelete calls per second, 0 is unlimited")
+	cli.AssignStringFlag(&journalFile, "journal-file", "", "Append every planned, started, succeeded and failed deletion to this journal file")
+	cli.AssignStringFlag(&resumeJournal, "resume", "", "Resume the deletion recorded in this journal, skipping completed entries and retrying failed ones")
+	cli.AssignStringFlag(&fromReport, "from-report", "", "Report of the deletion run to restore")
+	cli.AssignStringFlag(&planFile, "plan-file", "plan.json", "Plan file written by plan and executed by apply")
 
 	return cli.Run(ctx)
 } // Updated helper function with format support
 
 func initLogger(level string) error {
 	if valid := validateLogLevel(level); !valid {
-		return fmt.Errorf("invalid log level: %s", logLevel)
+		return fmt.Errorf("%w: invalid log level: %s", apperrors.ErrUsage, logLevel)
 	}
 	if valid := validateLogFormat(logFormat); !valid {
-		return fmt.Errorf("invalid log format: %s", logFormat)
+		return fmt.Errorf("%w: invalid log format: %s", apperrors.ErrUsage, logFormat)
+	}
+	if valid := validateBackend(backend); !valid {
+		return fmt.Errorf("%w: invalid backend: %s", apperrors.ErrUsage, backend)
 	}
 	logger.Init(logger.Config{
 		Level:  logLevel,
@@ -87,98 +228,361 @@ func validateLogFormat(format string) bool {
 	return slices.Contains(validFormats, format)
 }
 
-func checkHealth(rootCtx context.Context) {
-	_ = initLogger("info")
-	executor := createExecutor()
-	gcp.CheckHealth(rootCtx, executor)
+func validateBackend(name string) bool {
+	return slices.Contains(gcp.Backends, strings.ToLower(name))
 }
 
-func printTree(rootCtx context.Context) {
-	_ = initLogger(logLevel)
+func checkHealth(rootCtx context.Context) error {
+	if err := initLogger("info"); err != nil {
+		return err
+	}
+	client := createClient(createRateLimiter())
+	if err := client.CheckHealth(rootCtx); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	return nil
+}
+
+func printTree(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
 	ctx, cancelFunc := context.WithCancel(rootCtx)
 	defer cancelFunc()
 
-	log := logger.New(appID, "printTree")
+	client := createClient(createRateLimiter())
+	forest, discoveredAt, err := loadForest(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to load the resource tree: %w", err)
+	}
 
-	if rootFolderId == "" {
-		log.Error("rootFolderId is empty")
-		return
+	selection, err := selectEntries(forest)
+	if err != nil {
+		return fmt.Errorf("failed to apply filters: %w", err)
+	}
+	if showLiens && fromSnapshot == "" {
+		attachLiens(ctx, client, forestProjects(forest, nil))
 	}
 
-	executor := createExecutor()
-	tree := getStructure(ctx, rootFolderId, executor)
-	tree.Print()
+	forest.PrintWithOptions(models.PrintOptions{Selection: selection, Depth: displayDepth})
 
+	if err := saveSnapshot(forest, discoveredAt); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
 }
 
-func deleteResources(rootCtx context.Context) {
-	_ = initLogger(logLevel)
+func deleteResources(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
 	ctx, cancelFunc := context.WithCancel(rootCtx)
 	defer cancelFunc()
 
 	log := logger.New(appID, "deleteResources")
 
-	if rootFolderId == "" {
-		log.Error("rootFolderId is empty")
-		return
+	if fromSnapshot != "" && !dryRun {
+		return fmt.Errorf("%w: refusing to delete resources from a snapshot, use --dry-run or discover the live tree", apperrors.ErrUsage)
 	}
 
-	executor := createExecutor()
-	tree := getStructure(ctx, rootFolderId, executor)
-
-	tree.Print()
+	limiter := createRateLimiter()
+	client := createClient(limiter)
+	runJournal, err := openJournal()
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	if runJournal != nil {
+		defer func() { _ = runJournal.Close() }()
+	}
 
-	traversed := tree.PostOrderTraversal(tree.Root)
+	var traversed []models.Entry
+	if resumeJournal != "" {
+		traversed, err = resumeEntries(ctx, client, runJournal)
+	} else {
+		traversed, err = discoverDeletions(ctx, client)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to determine the resources to delete: %w", err)
+	}
 	log.DebugWithExtra("traversed", map[string]any{
 		"traversed": traversed,
 	})
 
-	projects := make([]models.Entry, 0)
-	folders := make([]models.Entry, 0)
+	runReport, err := deleteEntries(ctx, client, limiter, runJournal, traversed)
+	if err != nil {
+		return err
+	}
+	if err := saveReport(runReport); err != nil {
+		return errors.Join(runError(ctx, runReport), fmt.Errorf("failed to write report: %w", err))
+	}
+
+	return runError(ctx, runReport)
+}
+
+// discoverDeletions discovers and prints the tree, and returns the selected entries in deletion order
+func discoverDeletions(ctx context.Context, client gcp.ResourceClient) ([]models.Entry, error) {
+	forest, discoveredAt, err := loadForest(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the resource tree: %w", err)
+	}
+
+	selection, err := selectEntries(forest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply filters: %w", err)
+	}
+	if fromSnapshot == "" {
+		attachLiens(ctx, client, forestProjects(forest, selection))
+	}
+
+	forest.PrintWithOptions(models.PrintOptions{Selection: selection, Depth: displayDepth})
+
+	if err := saveSnapshot(forest, discoveredAt); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return deletionList(forest, selection), nil
+}
+
+func planResources(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New(appID, "planResources")
+
+	if fromSnapshot != "" {
+		return fmt.Errorf("%w: refusing to plan from a snapshot, the plan must match the live tree it is applied to", apperrors.ErrUsage)
+	}
+
+	client := createClient(createRateLimiter())
+	forest, discoveredAt, err := loadForest(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to load the resource tree: %w", err)
+	}
+
+	selection, err := selectEntries(forest)
+	if err != nil {
+		return fmt.Errorf("failed to apply filters: %w", err)
+	}
+	attachLiens(ctx, client, forestProjects(forest, selection))
+
+	forest.PrintWithOptions(models.PrintOptions{Selection: selection, Depth: displayDepth})
+
+	if err := saveSnapshot(forest, discoveredAt); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	discovery := models.PlanDiscovery{MaxDepth: maxDepth, Backend: strings.ToLower(backend)}
+	deletionPlan := models.NewPlan(forest, deletionList(forest, selection), discovery, discoveredAt)
+	if err := plan.Write(planFile, deletionPlan); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	log.Info(fmt.Sprintf("Plan with %d deletions written to %s", len(deletionPlan.Deletions), planFile))
+
+	return nil
+}
 
-	for _, entry := range traversed {
-		switch entry.Type {
-		case models.EntryTypeProject:
-			projects = append(projects, entry)
-		case models.EntryTypeFolder:
-			folders = append(folders, entry)
+func applyPlan(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New(appID, "applyPlan")
+
+	deletionPlan, err := plan.Read(planFile)
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	if err := usePlanDiscovery(deletionPlan.Discovery); err != nil {
+		return fmt.Errorf("invalid plan %s: %w", planFile, err)
+	}
+	limiter := createRateLimiter()
+	client := createClient(limiter)
+	forest, err := getStructure(ctx, deletionPlan.Roots, client)
+	if err != nil {
+		return fmt.Errorf("failed to load the resource tree: %w", err)
+	}
+	if err := deletionPlan.CheckDrift(forest); err != nil {
+		if deletionPlan.Discovery == nil {
+			err = fmt.Errorf("%w, the plan does not record its discovery settings, check that --max-depth %d and --backend %s match the plan run", err, maxDepth, backend)
 		}
+		return fmt.Errorf("refusing to apply the plan, run plan again and review the result: %w", err)
 	}
 
-	if enableConcurrency {
-		var wg sync.WaitGroup
-
-		for _, project := range projects {
-			wg.Add(1)
-			go func(p models.Entry) {
-				defer wg.Done()
-				err := gcp.DeleteProject(ctx, p.Id, dryRun, executor)
-				if err != nil {
-					log.Error("Failed to delete project", err)
-				}
-			}(project)
+	log.DebugWithExtra("Applying plan", map[string]any{
+		"path":        planFile,
+		"createdAt":   deletionPlan.CreatedAt,
+		"fingerprint": deletionPlan.Fingerprint,
+		"deletions":   len(deletionPlan.Deletions),
+	})
+	runJournal, err := openJournal()
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	if runJournal != nil {
+		defer func() { _ = runJournal.Close() }()
+	}
+
+	runReport, err := deleteEntries(ctx, client, limiter, runJournal, deletionPlan.Deletions)
+	if err != nil {
+		return err
+	}
+	if err := saveReport(runReport); err != nil {
+		return errors.Join(runError(ctx, runReport), fmt.Errorf("failed to write report: %w", err))
+	}
+
+	return runError(ctx, runReport)
+}
+
+// usePlanDiscovery discovers the roots of a plan with the settings of the plan run, the flags it overrides are logged.
+// Plans that do not record their settings keep the flags.
+func usePlanDiscovery(settings *models.PlanDiscovery) error {
+	log := logger.New(appID, "usePlanDiscovery")
+	if settings == nil {
+		return nil
+	}
+	if !validateBackend(settings.Backend) {
+		return fmt.Errorf("invalid backend: %s", settings.Backend)
+	}
+
+	if settings.MaxDepth != maxDepth {
+		log.Warn(fmt.Sprintf("Using --max-depth %d recorded in the plan instead of %d", settings.MaxDepth, maxDepth))
+		maxDepth = settings.MaxDepth
+	}
+	if !strings.EqualFold(settings.Backend, backend) {
+		log.Warn(fmt.Sprintf("Using --backend %s recorded in the plan instead of %s", settings.Backend, backend))
+		backend = settings.Backend
+	}
+
+	return nil
+}
+
+func restoreResources(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New(appID, "restoreResources")
+
+	if fromReport == "" {
+		return fmt.Errorf("%w: set --from-report to the report of the deletion run to restore", apperrors.ErrUsage)
+	}
+	deletionReport, err := report.Read(fromReport)
+	if err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+	if deletionReport.DryRun {
+		log.Warn("The report records a dry run, nothing was deleted")
+		return nil
+	}
+
+	limiter := createRateLimiter()
+	restoreReport := restoreEntries(ctx, createClient(limiter), limiter, deletionReport, time.Now())
+	printResults(os.Stdout, restoreReport.Results)
+	log.Info(fmt.Sprintf("Restored %d, skipped %d, failed %d, dry run %d",
+		restoreReport.Count(models.ResultRestored), restoreReport.Count(models.ResultSkipped),
+		restoreReport.Count(models.ResultFailed), restoreReport.Count(models.ResultDryRun)))
+	if err := writeReport(restoreReport); err != nil {
+		return errors.Join(runError(ctx, restoreReport), fmt.Errorf("failed to write report: %w", err))
+	}
+
+	return runError(ctx, restoreReport)
+}
+
+func listPending(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	client := createClient(createRateLimiter())
+	forest, _, err := loadForest(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to load the resource tree: %w", err)
+	}
+
+	projects := pendingProjects(forest)
+	if warning := purgeDateWarning(projects); warning != "" {
+		logger.New(appID, "listPending").Warn(warning)
+	}
+	printPending(os.Stdout, projects)
+
+	return nil
+}
+
+func diffResources(rootCtx context.Context) error {
+	if err := initLogger(logLevel); err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	if !validateDiffFormat(diffFormat) {
+		return fmt.Errorf("%w: invalid diff format: %s", apperrors.ErrUsage, diffFormat)
+	}
+	if diffFrom == "" {
+		return fmt.Errorf("%w: --diff-from is required", apperrors.ErrUsage)
+	}
+
+	before, err := snapshot.Read(diffFrom)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var after *models.Forest
+	if diffTo != "" {
+		loaded, err := snapshot.Read(diffTo)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
 		}
-		wg.Wait()
+		after = loaded.Forest()
 	} else {
-		for _, project := range projects {
-			err := gcp.DeleteProject(ctx, project.Id, dryRun, executor)
-			if err != nil {
-				log.Error("Failed to delete project", err)
-			}
+		client := createClient(createRateLimiter())
+		roots, err := rootEntries(ctx, client)
+		if err != nil {
+			return fmt.Errorf("failed to resolve root entries: %w", err)
+		}
+		after, err = getStructure(ctx, roots, client)
+		if err != nil {
+			return fmt.Errorf("failed to load the resource tree: %w", err)
 		}
 	}
 
-	for _, folder := range folders {
-		err := gcp.DeleteFolder(ctx, folder.Id, dryRun, executor)
+	diff := before.Forest().Diff(after)
+	if strings.ToLower(diffFormat) == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
 		if err != nil {
-			log.Error("Failed to delete folder", err)
+			return fmt.Errorf("failed to encode diff: %w", err)
 		}
+		fmt.Println(string(data))
+		return nil
 	}
 
+	diff.Print()
+
+	return nil
 }
 
-func logVersionDetails(_ context.Context) {
-	_ = initLogger("info")
+func validateDiffFormat(format string) bool {
+	return slices.Contains([]string{"tree", "json"}, strings.ToLower(format))
+}
+
+func logVersionDetails(_ context.Context) error {
+	if err := initLogger("info"); err != nil {
+		return err
+	}
 	log := logger.New(appID, "logVersionDetails")
 	log.Info(fmt.Sprintf("AppVersion=%s, GitCommit=%s", version.AppVersion, version.GitCommit))
+
+	return nil
 }
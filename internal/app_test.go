@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+func TestUsePlanDiscovery(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+
+	tests := []struct {
+		name     string
+		settings *models.PlanDiscovery
+		maxDepth int
+		backend  string
+		wantErr  bool
+	}{
+		{"plan without settings keeps the flags", nil, 5, gcp.BackendGCloud, false},
+		{"plan settings override the flags", &models.PlanDiscovery{MaxDepth: 2, Backend: gcp.BackendAPI}, 2, gcp.BackendAPI, false},
+		{"whole tree", &models.PlanDiscovery{MaxDepth: 0, Backend: gcp.BackendGCloud}, 0, gcp.BackendGCloud, false},
+		{"unknown backend", &models.PlanDiscovery{MaxDepth: 2, Backend: "grpc"}, 5, gcp.BackendGCloud, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxDepth, backend = 5, gcp.BackendGCloud
+			defer func() { maxDepth, backend = 0, gcp.BackendGCloud }()
+
+			err := usePlanDiscovery(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if maxDepth != tt.maxDepth || backend != tt.backend {
+				t.Errorf("Expected --max-depth %d and --backend %s, got %d and %s", tt.maxDepth, tt.backend, maxDepth, backend)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/backup"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+)
+
+// backupProject captures the IAM policy, enabled services and billing linkage of a project into the archive.
+// The labels and the parent are part of the project entry.
+func backupProject(ctx context.Context, client gcp.ResourceClient, archive *backup.Archive, project models.Entry) error {
+	policy, err := client.GetIAMPolicy(ctx, project.Id)
+	if err != nil {
+		return fmt.Errorf("failed to back up IAM policy of %s: %w", project.Id, err)
+	}
+
+	services, err := client.GetEnabledServices(ctx, project.Id)
+	if err != nil {
+		return fmt.Errorf("failed to back up enabled services of %s: %w", project.Id, err)
+	}
+
+	billing, err := client.GetBillingInfo(ctx, project.Id)
+	if err != nil {
+		return fmt.Errorf("failed to back up billing info of %s: %w", project.Id, err)
+	}
+
+	return archive.Add(&models.ProjectBackup{
+		Version:         models.BackupVersion,
+		BackedUpAt:      time.Now().UTC(),
+		Project:         project,
+		IAMPolicy:       policy,
+		EnabledServices: services,
+		Billing:         billing,
+	})
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/backup"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/journal"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/report"
+)
+
+// deletionList returns the selected entries of the forest in post order, leaving out the ones already pending deletion
+func deletionList(forest *models.Forest, selection models.Selection) []models.Entry {
+	log := logger.New(appID, "deletionList")
+
+	result := make([]models.Entry, 0)
+	for _, entry := range selection.Filter(forest.PostOrderTraversal()) {
+		if entry.PendingDeletion() {
+			log.Info(fmt.Sprintf("Skipping %s, deletion was already requested", entry.ResourceName()))
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// deleteEntries deletes the given entries with --delete-workers workers, each folder as soon as everything
+// below it finished. Every step is recorded in runJournal when set, and the time calls waited for limiter is recorded
+// in the report. It returns the record of every deletion, or an error when nothing could be attempted.
+func deleteEntries(ctx context.Context, client gcp.ResourceClient, limiter *gcp.RateLimiter, runJournal *journal.Journal, entries []models.Entry) (*models.Report, error) {
+	log := logger.New(appID, "deleteEntries")
+	runReport := models.NewReport(time.Now(), dryRun)
+
+	var archive *backup.Archive
+	if backupDir != "" {
+		var err error
+		if archive, err = backup.NewArchive(backupDir, runReport.StartedAt); err != nil {
+			return nil, fmt.Errorf("failed to create the backup archive, nothing was deleted: %w", err)
+		}
+		log.Info("Backing up projects to " + archive.Dir())
+	}
+
+	for _, entry := range entries {
+		recordJournal(runJournal, models.JournalPlanned, entry, nil)
+	}
+
+	results := scheduleDeletions(entries, workerCount(deleteWorkers), failFast, func(entry models.Entry) models.Result {
+		return journaled(runJournal, entry, func() models.Result {
+			if entry.Type == models.EntryTypeFolder {
+				return deleteFolder(ctx, client, entry)
+			}
+			return deleteProject(ctx, client, archive, entry)
+		})
+	})
+	for _, result := range results {
+		runReport.Add(result)
+	}
+	recordThrottling(runReport, client, limiter)
+	runReport.Finish(time.Now())
+
+	return runReport, nil
+}
+
+// recordThrottling records how many calls the client retried and how long calls waited for limiter so far
+func recordThrottling(runReport *models.Report, client gcp.ResourceClient, limiter *gcp.RateLimiter) {
+	if retrying, ok := client.(*gcp.RetryingClient); ok {
+		runReport.Retries = retrying.Retries()
+	}
+	if limiter != nil {
+		runReport.RateLimitWaitSeconds = limiter.Waited().Seconds()
+	}
+}
+
+// deleteProject deletes a single project. It backs the project up first when archive is set, a project
+// that could not be backed up is not deleted, and removes its liens when --remove-liens is set.
+func deleteProject(ctx context.Context, client gcp.ResourceClient, archive *backup.Archive, project models.Entry) models.Result {
+	log := logger.New(appID, "deleteProject")
+
+	result := models.Result{Entry: project}
+	if archive != nil {
+		if err := backupProject(ctx, client, archive, project); err != nil {
+			log.Error("Failed to back up project, skipping its deletion", err)
+			return failedResult(result, err)
+		}
+	}
+	if removeLiens {
+		removed, err := removeProjectLiens(ctx, client, project)
+		result.RemovedLiens = removed
+		if err != nil {
+			log.Error("Failed to remove liens", err)
+			return failedResult(result, err)
+		}
+	}
+
+	if err := client.DeleteProject(ctx, project.Id, dryRun); err != nil {
+		log.Error("Failed to delete project", err)
+		if errors.Is(err, apperrors.ErrLienPresent) && !removeLiens {
+			log.Warn("Project " + project.Id + " has a lien, use --remove-liens to remove it before deleting")
+		}
+		return failedResult(result, err)
+	}
+
+	return succeededResult(result)
+}
+
+func deleteFolder(ctx context.Context, client gcp.ResourceClient, folder models.Entry) models.Result {
+	log := logger.New(appID, "deleteFolder")
+
+	result := models.Result{Entry: folder}
+	if err := client.DeleteFolder(ctx, folder.Id, dryRun); err != nil {
+		log.Error("Failed to delete folder", err)
+		return failedResult(result, err)
+	}
+
+	return succeededResult(result)
+}
+
+// removeProjectLiens lists the current liens of the project and deletes them, returning the ones removed.
+// In dry run mode nothing is removed, so no liens are returned.
+func removeProjectLiens(ctx context.Context, client gcp.ResourceClient, project models.Entry) ([]models.Lien, error) {
+	log := logger.New(appID, "removeProjectLiens")
+
+	liens, err := client.GetLiens(ctx, project.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list liens of %s: %w", project.Id, err)
+	}
+
+	removed := make([]models.Lien, 0, len(liens))
+	for _, lien := range liens {
+		if err := client.DeleteLien(ctx, lien.Name, dryRun); err != nil {
+			return removed, fmt.Errorf("failed to remove lien %s from %s: %w", lien.Name, project.Id, err)
+		}
+		if dryRun {
+			log.Info(fmt.Sprintf("Would remove lien %s (%s) from project %s", lien.Name, lien.Origin, project.Id))
+			continue
+		}
+		log.Info(fmt.Sprintf("Removed lien %s (%s) from project %s", lien.Name, lien.Origin, project.Id))
+		removed = append(removed, lien)
+	}
+
+	return removed, nil
+}
+
+func failedResult(result models.Result, err error) models.Result {
+	result.Status = models.ResultFailed
+	result.Error = err.Error()
+	if class := apperrors.ClassOf(err); class != nil {
+		result.Cause = class.Error()
+	}
+	result.Time = time.Now().UTC()
+
+	return result
+}
+
+func succeededResult(result models.Result) models.Result {
+	result.Status = models.ResultDeleted
+	if dryRun {
+		result.Status = models.ResultDryRun
+	}
+	result.Time = time.Now().UTC()
+
+	return result
+}
+
+// saveReport writes the run report to --report-file when set and logs a summary of the run
+func saveReport(runReport *models.Report) error {
+	log := logger.New(appID, "saveReport")
+	log.Info(fmt.Sprintf("Deleted %d, failed %d, skipped %d, dry run %d, retries %d, rate limit wait %.1fs",
+		runReport.Count(models.ResultDeleted), runReport.Count(models.ResultFailed), runReport.Count(models.ResultSkipped), runReport.Count(models.ResultDryRun),
+		runReport.Retries, runReport.RateLimitWaitSeconds))
+
+	return writeReport(runReport)
+}
+
+// writeReport writes the run report to --report-file when set
+func writeReport(runReport *models.Report) error {
+	log := logger.New(appID, "writeReport")
+	if reportFile == "" {
+		return nil
+	}
+	if err := report.Write(reportFile, runReport); err != nil {
+		return err
+	}
+	log.Info("Report written to " + reportFile)
+
+	return nil
+}
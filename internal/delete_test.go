@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+func TestDeleteEntries_BackupArchiveError(t *testing.T) {
+	// A regular file where the backup directory should be created
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	backupDir = filepath.Join(blocker, "backups")
+	defer func() { backupDir = "" }()
+
+	entries := []models.Entry{*models.NewEntry("p", "Project", models.EntryTypeProject)}
+	runReport, err := deleteEntries(context.Background(), nil, nil, nil, entries)
+	if err == nil {
+		t.Fatal("Expected an error when the backup archive cannot be created")
+	}
+	if runReport != nil {
+		t.Errorf("Expected no report, got %+v", runReport)
+	}
+	if ExitCode(err) != ExitFailure {
+		t.Errorf("Expected exit code %d, got %d", ExitFailure, ExitCode(err))
+	}
+}
+
+// lienClient serves a single lien on every project
+type lienClient struct {
+	gcp.ResourceClient
+}
+
+func (c *lienClient) GetLiens(_ context.Context, projectId string) ([]models.Lien, error) {
+	return []models.Lien{{Name: "liens/" + projectId + "-abc", Origin: "servicenetworking.googleapis.com"}}, nil
+}
+
+func (c *lienClient) DeleteLien(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func TestRemoveProjectLiens(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	project := *models.NewEntry("p", "Project", models.EntryTypeProject)
+
+	tests := []struct {
+		name    string
+		dryRun  bool
+		removed int
+	}{
+		{"removes liens", false, 1},
+		{"dry run removes nothing", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dryRun = tt.dryRun
+			defer func() { dryRun = false }()
+
+			removed, err := removeProjectLiens(context.Background(), &lienClient{}, project)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(removed) != tt.removed {
+				t.Errorf("Expected %d removed liens, got %+v", tt.removed, removed)
+			}
+		})
+	}
+}
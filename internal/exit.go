@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// Exit codes of the process, the README documents them for CI pipelines
+const (
+	ExitOK             = 0
+	ExitFailure        = 1
+	ExitUsage          = 2
+	ExitPartialFailure = 3
+	ExitCancelled      = 130
+)
+
+// ExitCode maps the error returned by Run to the exit code of the process. Invalid flags, selectors and
+// --folder-path values that do not name exactly one folder are usage errors.
+// A run that failed completely, or stopped before deleting anything, exits with ExitFailure.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.Canceled):
+		return ExitCancelled
+	case errors.Is(err, apperrors.ErrUsage), errors.Is(err, apperrors.ErrInvalidSelector),
+		errors.Is(err, apperrors.ErrPathNotFound), errors.Is(err, apperrors.ErrPathAmbiguous):
+		return ExitUsage
+	case errors.Is(err, apperrors.ErrPartialFailure):
+		return ExitPartialFailure
+	default:
+		return ExitFailure
+	}
+}
+
+// runError returns the error of a finished run: the cancellation of ctx, a total failure when no entry
+// succeeded or a partial failure when only some did. Failed and skipped entries both count as unsuccessful.
+func runError(ctx context.Context, runReport *models.Report) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	total := len(runReport.Results)
+	unsuccessful := runReport.Count(models.ResultFailed) + runReport.Count(models.ResultSkipped)
+	switch {
+	case unsuccessful == 0:
+		return nil
+	case unsuccessful == total:
+		return fmt.Errorf("%w: none of %d entries succeeded", apperrors.ErrTotalFailure, total)
+	default:
+		return fmt.Errorf("%w: %d of %d entries failed or were skipped", apperrors.ErrPartialFailure, unsuccessful, total)
+	}
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, ExitOK},
+		{"usage", fmt.Errorf("%w: --diff-from is required", apperrors.ErrUsage), ExitUsage},
+		{"invalid selector", fmt.Errorf("failed to apply filters: %w", apperrors.ErrInvalidSelector), ExitUsage},
+		{"path not found", fmt.Errorf("failed to resolve root entries: %w: no folder named \"Sandboxes\"", apperrors.ErrPathNotFound), ExitUsage},
+		{"path ambiguous", fmt.Errorf("failed to resolve root entries: %w: 2 folders named \"Sandboxes\"", apperrors.ErrPathAmbiguous), ExitUsage},
+		{"partial failure", fmt.Errorf("%w: 1 of 3 entries failed or were skipped", apperrors.ErrPartialFailure), ExitPartialFailure},
+		{"total failure", fmt.Errorf("%w: none of 3 entries succeeded", apperrors.ErrTotalFailure), ExitFailure},
+		{"other error", errors.New("failed to read plan"), ExitFailure},
+		{"cancelled", errors.Join(context.Canceled, apperrors.ErrPartialFailure), ExitCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := ExitCode(tt.err); code != tt.expected {
+				t.Errorf("Expected exit code %d, got %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestRunError(t *testing.T) {
+	reportOf := func(statuses ...models.ResultStatus) *models.Report {
+		runReport := models.NewReport(time.Now(), false)
+		for i, status := range statuses {
+			runReport.Add(models.Result{Entry: *models.NewEntry(fmt.Sprint(i), "Project", models.EntryTypeProject), Status: status})
+		}
+		return runReport
+	}
+
+	tests := []struct {
+		name     string
+		report   *models.Report
+		expected error
+	}{
+		{"nothing to delete", reportOf(), nil},
+		{"all deleted", reportOf(models.ResultDeleted, models.ResultDryRun), nil},
+		{"some failed", reportOf(models.ResultDeleted, models.ResultFailed), apperrors.ErrPartialFailure},
+		{"some skipped", reportOf(models.ResultRestored, models.ResultSkipped), apperrors.ErrPartialFailure},
+		{"all failed", reportOf(models.ResultFailed, models.ResultSkipped), apperrors.ErrTotalFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runError(context.Background(), tt.report)
+			if (tt.expected == nil && err != nil) || !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	if err := runError(ctx, reportOf(models.ResultFailed)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled run to return context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/journal"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// openJournal opens the journal deletions are recorded to, --resume continues the journal it reads.
+// It returns nil when no journal is set and in dry run mode, where nothing is deleted.
+func openJournal() (*journal.Journal, error) {
+	log := logger.New(appID, "openJournal")
+
+	path := journalFile
+	if resumeJournal != "" {
+		path = resumeJournal
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if dryRun {
+		log.Info("Not writing the journal in dry run mode")
+		return nil, nil
+	}
+
+	return journal.Open(path)
+}
+
+// resumeEntries returns the planned entries of the --resume journal that were not deleted yet, in
+// their deletion order. The state of each one is checked first, entries already pending deletion
+// or gone are recorded as succeeded and skipped.
+func resumeEntries(ctx context.Context, client gcp.ResourceClient, runJournal *journal.Journal) ([]models.Entry, error) {
+	log := logger.New(appID, "resumeEntries")
+
+	records, err := journal.Read(resumeJournal)
+	if err != nil {
+		return nil, err
+	}
+	progress := models.ReplayJournal(records)
+	remaining := progress.Remaining()
+	log.Info(fmt.Sprintf("Resuming %s, %d of %d planned deletions remaining", resumeJournal, len(remaining), len(progress.Planned)))
+
+	result := make([]models.Entry, 0, len(remaining))
+	for _, entry := range remaining {
+		state, err := client.GetLifecycleState(ctx, entry)
+		if errors.Is(err, apperrors.ErrNotFound) {
+			log.Info(fmt.Sprintf("Skipping %s, it no longer exists", entry.ResourceName()))
+			recordJournal(runJournal, models.JournalSucceeded, entry, nil)
+			continue
+		}
+		if err != nil {
+			log.Warn(fmt.Sprintf("Failed to check the state of %s, deleting it again", entry.ResourceName()))
+			result = append(result, entry)
+			continue
+		}
+		if state == models.LifecycleStateDeleteRequested {
+			log.Info(fmt.Sprintf("Skipping %s, deletion was already requested", entry.ResourceName()))
+			recordJournal(runJournal, models.JournalSucceeded, entry, nil)
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// journaled records the start and the outcome of a single deletion
+func journaled(runJournal *journal.Journal, entry models.Entry, deleteEntry func() models.Result) models.Result {
+	recordJournal(runJournal, models.JournalStarted, entry, nil)
+	result := deleteEntry()
+	if result.Status == models.ResultFailed {
+		recordJournal(runJournal, models.JournalFailed, entry, errors.New(result.Error))
+	} else {
+		recordJournal(runJournal, models.JournalSucceeded, entry, nil)
+	}
+
+	return result
+}
+
+// recordJournal appends an event to the journal when one is open, a failed write is only logged
+func recordJournal(runJournal *journal.Journal, event models.JournalEvent, entry models.Entry, cause error) {
+	if runJournal == nil {
+		return
+	}
+	if err := runJournal.Record(event, entry, cause); err != nil {
+		logger.New(appID, "recordJournal").Error("Failed to write journal", err)
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/journal"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// lifecycleClient reports the lifecycle state of entries by id, or the error set for them
+type lifecycleClient struct {
+	gcp.ResourceClient
+
+	states  map[string]string
+	errors  map[string]error
+	checked []string
+}
+
+func (c *lifecycleClient) GetLifecycleState(_ context.Context, entry models.Entry) (string, error) {
+	c.checked = append(c.checked, entry.Id)
+	if err, found := c.errors[entry.Id]; found {
+		return "", err
+	}
+	return c.states[entry.Id], nil
+}
+
+func TestResumeEntries(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	planned := []models.Entry{
+		*models.NewEntry("done", "Done", models.EntryTypeProject),
+		*models.NewEntry("p", "Project", models.EntryTypeProject),
+		*models.NewEntry("f", "Folder", models.EntryTypeFolder),
+	}
+	plannedByID := make(map[string]models.Entry)
+	for _, entry := range planned {
+		plannedByID[entry.Id] = entry
+	}
+
+	tests := []struct {
+		name      string
+		states    map[string]string
+		errors    map[string]error
+		remaining []string
+		succeeded []string
+	}{
+		{
+			name:      "still active",
+			states:    map[string]string{"p": models.LifecycleStateActive, "f": models.LifecycleStateActive},
+			remaining: []string{"p", "f"},
+		},
+		{
+			name:      "deletion already requested",
+			states:    map[string]string{"p": models.LifecycleStateDeleteRequested, "f": models.LifecycleStateActive},
+			remaining: []string{"f"},
+			succeeded: []string{"p"},
+		},
+		{
+			name:      "no longer exists",
+			states:    map[string]string{"p": models.LifecycleStateActive},
+			errors:    map[string]error{"f": apperrors.ErrNotFound},
+			remaining: []string{"p"},
+			succeeded: []string{"f"},
+		},
+		{
+			name:      "state check failed",
+			states:    map[string]string{"f": models.LifecycleStateActive},
+			errors:    map[string]error{"p": errors.New("connection reset")},
+			remaining: []string{"p", "f"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A run that planned done, p and f, and only finished deleting done
+			resumeJournal = filepath.Join(t.TempDir(), "journal.jsonl")
+			defer func() { resumeJournal = "" }()
+			previous, err := journal.Open(resumeJournal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, entry := range planned {
+				recordJournal(previous, models.JournalPlanned, entry, nil)
+			}
+			recordJournal(previous, models.JournalSucceeded, planned[0], nil)
+			if err := previous.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			runJournal, err := journal.Open(resumeJournal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := &lifecycleClient{states: tt.states, errors: tt.errors}
+			remaining, err := resumeEntries(context.Background(), client, runJournal)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if err := runJournal.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Join(client.checked, ",") != "p,f" {
+				t.Errorf("Expected only p and f to be checked, got %v", client.checked)
+			}
+			ids := make([]string, 0, len(remaining))
+			for _, entry := range remaining {
+				ids = append(ids, entry.Id)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.remaining, ",") {
+				t.Errorf("Expected %v to be retried, got %v", tt.remaining, ids)
+			}
+
+			records, err := journal.Read(resumeJournal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			progress := models.ReplayJournal(records)
+			for _, id := range tt.succeeded {
+				entry := plannedByID[id]
+				name := entry.ResourceName()
+				if event := progress.Last[name]; event != models.JournalSucceeded {
+					t.Errorf("Expected %s to be journaled as succeeded, got %q", name, event)
+				}
+			}
+			if len(progress.Remaining()) != len(tt.remaining) {
+				t.Errorf("Expected %d entries left in the journal, got %d", len(tt.remaining), len(progress.Remaining()))
+			}
+		})
+	}
+}
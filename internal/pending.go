@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// pendingProjects returns every project of the forest whose deletion was already requested
+func pendingProjects(forest *models.Forest) []models.Entry {
+	result := make([]models.Entry, 0)
+	for _, entry := range forest.PostOrderTraversal() {
+		if entry.Type == models.EntryTypeProject && entry.PendingDeletion() {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// purgeDateWarning returns the warning to log when the purge date of some pending projects is unknown,
+// which happens with every project discovered with the gcloud backend
+func purgeDateWarning(projects []models.Entry) string {
+	unknown := 0
+	for _, project := range projects {
+		if _, known := project.PurgeTime(); !known {
+			unknown++
+		}
+	}
+	if unknown == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("The purge date of %d of %d pending projects is unknown, the gcloud backend does not report when deletion was requested, run with --backend api to see it", unknown, len(projects))
+}
+
+// printPending renders the pending projects as a table. The purge date is unknown when
+// the backend does not report when deletion was requested, as with gcloud.
+func printPending(out io.Writer, projects []models.Entry) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "PROJECT ID\tNAME\tPATH\tDELETE REQUESTED\tPURGE DATE")
+	for _, project := range projects {
+		requested, purge := "unknown", "unknown"
+		if purgeTime, known := project.PurgeTime(); known {
+			requested = project.DeleteTime.UTC().Format(time.DateOnly)
+			purge = purgeTime.UTC().Format(time.DateOnly)
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", project.Id, project.Name, project.Path(), requested, purge)
+	}
+	_ = writer.Flush()
+}
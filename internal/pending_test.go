@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+func TestPurgeDateWarning(t *testing.T) {
+	// newPending returns a project pending deletion, requested at deleteTime when it is known
+	newPending := func(id string, deleteTime time.Time) models.Entry {
+		project := *models.NewEntry(id, "Project", models.EntryTypeProject)
+		project.LifecycleState = models.LifecycleStateDeleteRequested
+		project.DeleteTime = deleteTime
+		return project
+	}
+	requested := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		projects []models.Entry
+		expected string
+	}{
+		{"no pending projects", nil, ""},
+		{"api backend", []models.Entry{newPending("a", requested)}, ""},
+		{"gcloud backend", []models.Entry{newPending("a", time.Time{}), newPending("b", time.Time{})}, "2 of 2 pending projects"},
+		{"mixed", []models.Entry{newPending("a", requested), newPending("b", time.Time{})}, "1 of 2 pending projects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := purgeDateWarning(tt.projects)
+			if tt.expected == "" && warning != "" {
+				t.Errorf("Expected no warning, got %q", warning)
+			}
+			if !strings.Contains(warning, tt.expected) || (tt.expected != "" && !strings.Contains(warning, "--backend api")) {
+				t.Errorf("Expected a warning about %q pointing to --backend api, got %q", tt.expected, warning)
+			}
+		})
+	}
+}
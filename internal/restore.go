@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// restoreEntries undeletes the deleted entries of a past run top down, the reverse of the deletion order.
+// Entries past their recovery window, or whose parent folder could not be restored, are skipped.
+func restoreEntries(ctx context.Context, client gcp.ResourceClient, limiter *gcp.RateLimiter, deletionReport *models.Report, now time.Time) *models.Report {
+	log := logger.New(appID, "restoreEntries")
+	runReport := models.NewReport(now, dryRun)
+
+	// notRestored holds the resource names of folders that are still deleted after this run
+	notRestored := make(map[string]bool)
+	for _, deleted := range deletionReport.RestoreOrder() {
+		result := models.Result{Entry: deleted.Entry}
+		name := deleted.Entry.ResourceName()
+
+		switch {
+		case now.After(deleted.RecoveryDeadline()):
+			result = skippedResult(result, "past its recovery window, purged on "+deleted.RecoveryDeadline().UTC().Format(time.DateOnly))
+		case notRestored[deleted.Entry.Parent]:
+			result = skippedResult(result, "parent "+deleted.Entry.Parent+" was not restored")
+		default:
+			result = restoreEntry(ctx, client, result)
+		}
+
+		if result.Status == models.ResultSkipped || result.Status == models.ResultFailed {
+			notRestored[name] = true
+		}
+		log.Info(fmt.Sprintf("%s %s", resultLabel(result), name))
+		runReport.Add(result)
+	}
+	recordThrottling(runReport, client, limiter)
+	runReport.Finish(time.Now())
+
+	return runReport
+}
+
+func restoreEntry(ctx context.Context, client gcp.ResourceClient, result models.Result) models.Result {
+	log := logger.New(appID, "restoreEntry")
+
+	var err error
+	switch result.Entry.Type {
+	case models.EntryTypeProject:
+		err = client.UndeleteProject(ctx, result.Entry.Id, dryRun)
+	case models.EntryTypeFolder:
+		err = client.UndeleteFolder(ctx, result.Entry.Id, dryRun)
+	default:
+		err = fmt.Errorf("cannot restore %s", result.Entry.ResourceName())
+	}
+	if err != nil {
+		log.Error("Failed to restore "+result.Entry.ResourceName(), err)
+		return failedResult(result, err)
+	}
+
+	result.Status = models.ResultRestored
+	if dryRun {
+		result.Status = models.ResultDryRun
+	}
+	result.Time = time.Now().UTC()
+
+	return result
+}
+
+func skippedResult(result models.Result, reason string) models.Result {
+	result.Status = models.ResultSkipped
+	result.Reason = reason
+	result.Time = time.Now().UTC()
+
+	return result
+}
+
+// resultLabel capitalizes the status of a result for log lines
+func resultLabel(result models.Result) string {
+	switch result.Status {
+	case models.ResultRestored:
+		return "Restored"
+	case models.ResultSkipped:
+		return "Skipped"
+	case models.ResultFailed:
+		return "Failed to restore"
+	default:
+		return "Would restore"
+	}
+}
+
+// printResults renders the results of a run as a table
+func printResults(out io.Writer, results []models.Result) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tID\tNAME\tSTATUS\tDETAIL")
+	for _, result := range results {
+		detail := result.Reason
+		if result.Error != "" {
+			detail = result.Error
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", result.Entry.Type, result.Entry.Id, result.Entry.Name, result.Status, detail)
+	}
+	_ = writer.Flush()
+}
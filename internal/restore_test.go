@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// undeleteClient records the entries it restores and fails to restore the ones listed in failing
+type undeleteClient struct {
+	gcp.ResourceClient
+
+	failing  map[string]bool
+	restored []string
+}
+
+func (c *undeleteClient) undelete(id string) error {
+	if c.failing[id] {
+		return apperrors.ErrPermissionDenied
+	}
+	c.restored = append(c.restored, id)
+	return nil
+}
+
+func (c *undeleteClient) UndeleteProject(_ context.Context, projectId string, _ bool) error {
+	return c.undelete(projectId)
+}
+
+func (c *undeleteClient) UndeleteFolder(_ context.Context, folderId string, _ bool) error {
+	return c.undelete(folderId)
+}
+
+func TestRestoreEntries(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	expired := now.Add(-models.PurgeWindow - time.Hour)
+
+	// deleted builds the report of a run that deleted project p, then its parent folder f, then project q of the organization
+	deleted := func(projectTime, folderTime time.Time) *models.Report {
+		folder := *models.NewEntry("f", "Folder", models.EntryTypeFolder)
+		folder.Parent = "organizations/1"
+		project := *models.NewEntry("p", "Project", models.EntryTypeProject)
+		project.Parent = "folders/f"
+		other := *models.NewEntry("q", "Other", models.EntryTypeProject)
+		other.Parent = "organizations/1"
+
+		deletionReport := models.NewReport(recent, false)
+		deletionReport.Add(models.Result{Entry: project, Status: models.ResultDeleted, Time: projectTime})
+		deletionReport.Add(models.Result{Entry: folder, Status: models.ResultDeleted, Time: folderTime})
+		deletionReport.Add(models.Result{Entry: other, Status: models.ResultDeleted, Time: recent})
+		return deletionReport
+	}
+
+	tests := []struct {
+		name           string
+		deletionReport *models.Report
+		failing        map[string]bool
+		statuses       map[string]models.ResultStatus
+		reasons        map[string]string
+		restored       []string
+	}{
+		{
+			name:           "within the recovery window",
+			deletionReport: deleted(recent, recent),
+			statuses:       map[string]models.ResultStatus{"f": models.ResultRestored, "p": models.ResultRestored, "q": models.ResultRestored},
+			restored:       []string{"q", "f", "p"},
+		},
+		{
+			name:           "past the recovery window",
+			deletionReport: deleted(expired, expired),
+			statuses:       map[string]models.ResultStatus{"f": models.ResultSkipped, "p": models.ResultSkipped, "q": models.ResultRestored},
+			reasons:        map[string]string{"f": "past its recovery window", "p": "past its recovery window"},
+			restored:       []string{"q"},
+		},
+		{
+			name:           "parent past the recovery window",
+			deletionReport: deleted(recent, expired),
+			statuses:       map[string]models.ResultStatus{"f": models.ResultSkipped, "p": models.ResultSkipped, "q": models.ResultRestored},
+			reasons:        map[string]string{"f": "past its recovery window", "p": "parent folders/f was not restored"},
+			restored:       []string{"q"},
+		},
+		{
+			name:           "parent failed to restore",
+			deletionReport: deleted(recent, recent),
+			failing:        map[string]bool{"f": true},
+			statuses:       map[string]models.ResultStatus{"f": models.ResultFailed, "p": models.ResultSkipped, "q": models.ResultRestored},
+			reasons:        map[string]string{"p": "parent folders/f was not restored"},
+			restored:       []string{"q"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &undeleteClient{failing: tt.failing}
+			runReport := restoreEntries(context.Background(), client, nil, tt.deletionReport, now)
+
+			if len(runReport.Results) != len(tt.statuses) {
+				t.Fatalf("Expected %d results, got %d", len(tt.statuses), len(runReport.Results))
+			}
+			for _, result := range runReport.Results {
+				id := result.Entry.Id
+				if result.Status != tt.statuses[id] {
+					t.Errorf("Expected %s to be %s, got %s", id, tt.statuses[id], result.Status)
+				}
+				if !strings.HasPrefix(result.Reason, tt.reasons[id]) {
+					t.Errorf("Expected the reason for %s to start with %q, got %q", id, tt.reasons[id], result.Reason)
+				}
+			}
+			if strings.Join(client.restored, ",") != strings.Join(tt.restored, ",") {
+				t.Errorf("Expected %v to be restored, got %v", tt.restored, client.restored)
+			}
+		})
+	}
+}
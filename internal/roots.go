@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+)
+
+// rootEntries builds the discovery roots from --folder-id, --folder-ids-file, --folder-path or --organization-id
+func rootEntries(ctx context.Context, client gcp.ResourceClient) ([]models.Entry, error) {
+	folderIds := append([]string{}, rootFolderIds...)
+	if rootFolderIdsFile != "" {
+		ids, err := readIdsFile(rootFolderIdsFile)
+		if err != nil {
+			return nil, err
+		}
+		folderIds = append(folderIds, ids...)
+	}
+
+	hasFolders := len(folderIds) > 0 || len(rootFolderPaths) > 0
+	switch {
+	case hasFolders && organizationId != "":
+		return nil, fmt.Errorf("%w: --folder-id, --folder-path and --organization-id are mutually exclusive", errors.ErrUsage)
+	case organizationId != "":
+		return []models.Entry{*models.NewEntry(organizationId, organizationId, models.EntryTypeOrganization)}, nil
+	case !hasFolders:
+		return nil, fmt.Errorf("%w: either --folder-id, --folder-path or --organization-id is required", errors.ErrUsage)
+	}
+
+	roots := make([]models.Entry, 0, len(folderIds)+len(rootFolderPaths))
+	seen := make(map[string]bool, len(folderIds)+len(rootFolderPaths))
+	for _, id := range folderIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		roots = append(roots, *models.NewEntry(id, id, models.EntryTypeFolder))
+	}
+	for _, path := range rootFolderPaths {
+		root, err := gcp.ResolveFolderPath(ctx, client, path)
+		if err != nil {
+			return nil, err
+		}
+		if seen[root.Id] {
+			continue
+		}
+		seen[root.Id] = true
+		roots = append(roots, root)
+	}
+
+	return roots, nil
+}
+
+// readIdsFile reads one folder id per line, blank lines and lines starting with # are ignored
+func readIdsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrFileDoesNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+
+	return ids, scanner.Err()
+}
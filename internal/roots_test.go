@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestRootEntries_UsageErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		folderIds    []string
+		organization string
+	}{
+		{"no root", nil, ""},
+		{"folder and organization", []string{"1"}, "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootFolderIds, organizationId = tt.folderIds, tt.organization
+			defer func() { rootFolderIds, organizationId = nil, "" }()
+
+			_, err := rootEntries(context.Background(), nil)
+			if !errors.Is(err, apperrors.ErrUsage) {
+				t.Errorf("Expected a usage error, got %v", err)
+			}
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("Expected exit code %d, got %d", ExitUsage, ExitCode(err))
+			}
+		})
+	}
+}
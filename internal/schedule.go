@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// scheduledResult is the outcome of a deletion run by a scheduler worker
+type scheduledResult struct {
+	entry  models.Entry
+	result models.Result
+}
+
+// scheduleDeletions runs deleteEntry for every entry with a fixed pool of workers. A folder is handed to a
+// worker the moment every entry below it finished, so independent subtrees progress in parallel.
+// A failed deletion skips every folder above it. With failFast no further deletion is started after the
+// first failure, the ones in flight still finish. It returns the results in the order the deletions finished.
+func scheduleDeletions(entries []models.Entry, workers int, failFast bool, deleteEntry func(models.Entry) models.Result) []models.Result {
+	log := logger.New(appID, "scheduleDeletions")
+	graph := models.NewDeletionGraph(entries)
+
+	tasks := make(chan models.Entry)
+	done := make(chan scheduledResult)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for entry := range tasks {
+				done <- scheduledResult{entry: entry, result: deleteEntry(entry)}
+			}
+		}()
+	}
+	defer close(tasks)
+
+	results := make([]models.Result, 0, len(entries))
+	finished := make(map[string]bool, len(entries))
+	queue := graph.Ready()
+	inFlight := 0
+	var abortedBy *models.Entry
+	for len(queue) > 0 || inFlight > 0 {
+		var next chan models.Entry
+		var entry models.Entry
+		if len(queue) > 0 {
+			next = tasks
+			entry = queue[0]
+		}
+
+		select {
+		case next <- entry:
+			queue = queue[1:]
+			inFlight++
+		case result := <-done:
+			inFlight--
+			results = append(results, result.result)
+			finished[result.entry.ResourceName()] = true
+			if result.result.Status != models.ResultFailed {
+				if released, found := graph.Done(result.entry); found && abortedBy == nil {
+					queue = append(queue, released)
+				}
+				continue
+			}
+
+			reason := "blocked by failed deletion of " + result.entry.ResourceName()
+			if result.result.Cause != "" {
+				reason += " (" + result.result.Cause + ")"
+			}
+			for _, folder := range graph.Fail(result.entry) {
+				log.Warn("Skipping " + folder.ResourceName() + ", deletion of " + result.entry.ResourceName() + " failed")
+				results = append(results, skippedResult(models.Result{Entry: folder}, reason))
+				finished[folder.ResourceName()] = true
+			}
+			if failFast && abortedBy == nil {
+				log.Warn("Aborting the run, deletion of " + result.entry.ResourceName() + " failed")
+				abortedBy = &result.entry
+				queue = nil
+			}
+		}
+	}
+
+	if abortedBy != nil {
+		for _, entry := range entries {
+			if !finished[entry.ResourceName()] {
+				results = append(results, skippedResult(models.Result{Entry: entry}, "run aborted after failed deletion of "+abortedBy.ResourceName()))
+			}
+		}
+	}
+
+	return results
+}
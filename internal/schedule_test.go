@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// newScheduleTestEntries returns root > {a > {pa}, b > {pb}} in post order
+func newScheduleTestEntries() []models.Entry {
+	root := models.NewNode(models.NewEntry("root", "Root", models.EntryTypeFolder), nil)
+	a := models.NewNode(models.NewEntry("a", "A", models.EntryTypeFolder), []models.Entry{*models.NewEntry("pa", "PA", models.EntryTypeProject)})
+	b := models.NewNode(models.NewEntry("b", "B", models.EntryTypeFolder), []models.Entry{*models.NewEntry("pb", "PB", models.EntryTypeProject)})
+	root.AddChild(a)
+	root.AddChild(b)
+	root.Link()
+	entries := models.NewForest(&models.Tree{Root: root}).PostOrderTraversal()
+
+	return entries
+}
+
+func TestScheduleDeletions_FoldersWaitForTheirSubtree(t *testing.T) {
+	entries := newScheduleTestEntries()
+
+	// pb only finishes once folder a was deleted, which is impossible if folders wait for unrelated subtrees
+	aDeleted := make(chan struct{})
+	var mu sync.Mutex
+	finished := make(map[string]bool)
+	results := scheduleDeletions(entries, 2, false, func(entry models.Entry) models.Result {
+		switch entry.Id {
+		case "pb":
+			<-aDeleted
+		case "a":
+			close(aDeleted)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, child := range map[string][]string{"root": {"a", "b"}, "a": {"pa"}, "b": {"pb"}}[entry.Id] {
+			if !finished[child] {
+				t.Errorf("Expected %s to finish before %s", child, entry.Id)
+			}
+		}
+		finished[entry.Id] = true
+
+		return models.Result{Entry: entry, Status: models.ResultDeleted}
+	})
+
+	if len(results) != len(entries) {
+		t.Fatalf("Expected %d results, got %d", len(entries), len(results))
+	}
+	if last := results[len(results)-1].Entry.Id; last != "root" {
+		t.Errorf("Expected root to be deleted last, got %s", last)
+	}
+}
+
+// statusesOf runs the scheduler with one worker, failing the deletion of the given entry
+func statusesOf(failing string, failFast bool) map[string]models.Result {
+	deleted := func(entry models.Entry) models.Result {
+		if entry.Id == failing {
+			return models.Result{Entry: entry, Status: models.ResultFailed, Cause: "lien present"}
+		}
+		return models.Result{Entry: entry, Status: models.ResultDeleted}
+	}
+
+	statuses := make(map[string]models.Result)
+	for _, result := range scheduleDeletions(newScheduleTestEntries(), 1, failFast, deleted) {
+		statuses[result.Entry.Id] = result
+	}
+
+	return statuses
+}
+
+func TestScheduleDeletions_FailureBlocksAncestors(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	statuses := statusesOf("pa", false)
+
+	expected := map[string]models.ResultStatus{
+		"pa":   models.ResultFailed,
+		"a":    models.ResultSkipped,
+		"pb":   models.ResultDeleted,
+		"b":    models.ResultDeleted,
+		"root": models.ResultSkipped,
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("Expected %d results, got %v", len(expected), statuses)
+	}
+	for id, status := range expected {
+		if statuses[id].Status != status {
+			t.Errorf("Expected %s to be %s, got %s", id, status, statuses[id].Status)
+		}
+	}
+	for _, id := range []string{"a", "root"} {
+		if reason := statuses[id].Reason; reason != "blocked by failed deletion of projects/pa (lien present)" {
+			t.Errorf("Expected %s to point to pa, got %q", id, reason)
+		}
+	}
+}
+
+func TestScheduleDeletions_FailFast(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	statuses := statusesOf("pa", true)
+
+	if statuses["pa"].Status != models.ResultFailed {
+		t.Errorf("Expected pa to fail, got %s", statuses["pa"].Status)
+	}
+	for _, id := range []string{"a", "root", "pb", "b"} {
+		if statuses[id].Status != models.ResultSkipped {
+			t.Errorf("Expected %s to be skipped, got %s", id, statuses[id].Status)
+		}
+	}
+	if reason := statuses["pb"].Reason; !strings.HasPrefix(reason, "run aborted") {
+		t.Errorf("Expected pb to be skipped since the run was aborted, got %q", reason)
+	}
+}
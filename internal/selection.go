@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/selector"
+)
+
+// selectors builds the selector set from --match, --exclude, --include-label, --exclude-label,
+// --older-than and --created-before
+func selectors() (selector.Set, error) {
+	set := make(selector.Set, 0, 4+len(includeLabels)+len(excludeLabels))
+	if len(matchPatterns) > 0 {
+		patterns, err := parsePatterns(matchPatterns)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.Match{Patterns: patterns})
+	}
+	if len(excludePatterns) > 0 {
+		patterns, err := parsePatterns(excludePatterns)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.Exclude{Patterns: patterns})
+	}
+	for _, expression := range includeLabels {
+		label, err := selector.ParseLabel(expression)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.IncludeLabel{Label: label})
+	}
+	for _, expression := range excludeLabels {
+		label, err := selector.ParseLabel(expression)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.ExcludeLabel{Label: label})
+	}
+	if olderThan != "" {
+		age, err := selector.ParseAge(olderThan)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.OlderThan(age, olderThan, time.Now()))
+	}
+	if createdBefore != "" {
+		cutoff, err := selector.ParseDate(createdBefore)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, selector.CreatedBefore{Cutoff: cutoff, Description: "created before " + createdBefore})
+	}
+
+	return set, nil
+}
+
+// selectEntries decides for every folder and project of the forest whether it takes part in the deletion
+func selectEntries(forest *models.Forest) (models.Selection, error) {
+	set, err := selectors()
+	if err != nil {
+		return nil, err
+	}
+
+	return forest.Select(set.Decide), nil
+}
+
+func parsePatterns(expressions []string) ([]selector.Pattern, error) {
+	patterns := make([]selector.Pattern, 0, len(expressions))
+	for _, expression := range expressions {
+		pattern, err := selector.ParsePattern(expression)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, nil
+}
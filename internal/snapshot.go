@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/snapshot"
+)
+
+// loadForest discovers the configured roots, or loads the forest from --from-snapshot when set.
+// Tags are only fetched when the forest is written to --output-file. It also returns when the forest was discovered.
+func loadForest(ctx context.Context, client gcp.ResourceClient) (*models.Forest, time.Time, error) {
+	log := logger.New(appID, "loadForest")
+
+	if fromSnapshot != "" {
+		loaded, err := snapshot.Read(fromSnapshot)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		log.DebugWithExtra("Loaded snapshot", map[string]any{
+			"path":         fromSnapshot,
+			"discoveredAt": loaded.DiscoveredAt,
+		})
+		return loaded.Forest(), loaded.DiscoveredAt, nil
+	}
+
+	roots, err := rootEntries(ctx, client)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	discoveredAt := time.Now()
+	forest, err := getStructure(ctx, roots, client)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if outputFile != "" {
+		attachTags(ctx, client, forest.Entries())
+	}
+
+	return forest, discoveredAt, nil
+}
+
+// saveSnapshot writes the forest to --output-file when set
+func saveSnapshot(forest *models.Forest, discoveredAt time.Time) error {
+	if outputFile == "" {
+		return nil
+	}
+
+	log := logger.New(appID, "saveSnapshot")
+	if err := snapshot.Write(outputFile, models.NewSnapshot(forest, discoveredAt)); err != nil {
+		return err
+	}
+	log.Info("Snapshot written to " + outputFile)
+
+	return nil
+}
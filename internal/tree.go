@@ -2,102 +2,284 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
 )
 
-func getStructure(ctx context.Context, rootFolderId string, executor gcp.CommandExecutor) *models.Tree {
-	tree := models.NewTree()
-	rootEntry := models.NewEntry(rootFolderId, rootFolderId, models.EntryTypeFolder)
+// discoveryTask is a folder or organization waiting to be listed
+type discoveryTask struct {
+	entry models.Entry
+	// parent is the node the listed folder is attached to, nil for a root
+	parent *models.Node
+	// index is the position of the folder among its siblings, tree is the position of its root
+	index int
+	tree  int
+	depth int
+}
+
+// discoveryResult is a listed folder along with the subfolders still to list, or the error listing it
+type discoveryResult struct {
+	task    discoveryTask
+	node    *models.Node
+	folders []models.Entry
+	err     error
+}
+
+// workerCount returns the configured number of workers, or the concurrency limit when it is not set
+func workerCount(configured int) int {
+	switch {
+	case configured > 0:
+		return configured
+	case enableConcurrency:
+		return max(concurrecyLimit, 1)
+	default:
+		return 1
+	}
+}
+
+// getStructure discovers every root breadth first. The roots share one queue served by --discovery-workers
+// workers, so the number of goroutines and in-flight calls stays bounded however wide the hierarchy is.
+// It fails when any folder could not be listed, since an incomplete tree must not be printed or deleted as if it were whole.
+func getStructure(ctx context.Context, roots []models.Entry, client gcp.ResourceClient) (*models.Forest, error) {
+	trees, err := discover(ctx, roots, client, workerCount(discoveryWorkers))
+	if err != nil {
+		return nil, err
+	}
+
+	forest := models.NewForest()
+	for _, tree := range trees {
+		forest.Add(tree)
+	}
+	forest.Dedupe()
+
+	return forest, nil
+}
+
+// discover lists the roots and all their subfolders with a fixed pool of workers and returns one tree per root.
+// Only this goroutine touches the trees, each listed folder is attached as soon as its result arrives.
+// Discovery continues past a folder that could not be listed, and the errors of all of them are returned together.
+func discover(ctx context.Context, roots []models.Entry, client gcp.ResourceClient, workers int) ([]*models.Tree, error) {
+	log := logger.New(appID, "discover")
+	log.DebugWithExtra("Starting discovery", map[string]any{
+		"roots":   len(roots),
+		"workers": workers,
+	})
 
-	if enableConcurrency {
-		tree.Root = getTreeWithConcurrentSubfolders(ctx, *rootEntry, executor)
-	} else {
-		// EXISTING: Use your original sequential version
-		tree.Root = getTree(ctx, *rootEntry, executor)
+	tasks := make(chan discoveryTask)
+	results := make(chan discoveryResult)
+	for i := 0; i < workers; i++ {
+		go discoveryWorker(ctx, client, tasks, results)
 	}
+	defer close(tasks)
+
+	trees := make([]*models.Tree, len(roots))
+	queue := make([]discoveryTask, 0, len(roots))
+	for i, root := range roots {
+		trees[i] = models.NewTree()
+		queue = append(queue, discoveryTask{entry: root, tree: i})
+	}
+
+	// positions remembers the sibling index of every attached folder, results arrive in any order
+	positions := make(map[*models.Node]int)
+	var errs []error
+	inFlight := 0
+	for len(queue) > 0 || inFlight > 0 {
+		var next chan discoveryTask
+		var task discoveryTask
+		if len(queue) > 0 {
+			task = queue[0]
+			if node := truncatedNode(task.entry, task.depth); node != nil {
+				queue = queue[1:]
+				attachNode(trees, positions, task, node)
+				continue
+			}
+			next = tasks
+		}
+
+		select {
+		case next <- task:
+			queue = queue[1:]
+			inFlight++
+		case result := <-results:
+			inFlight--
+			if result.err != nil {
+				errs = append(errs, result.err)
+			}
+			if result.node == nil {
+				continue
+			}
+			attachNode(trees, positions, result.task, result.node)
+			for i, folder := range result.folders {
+				queue = append(queue, discoveryTask{entry: folder, parent: result.node, index: i, tree: result.task.tree, depth: result.task.depth + 1})
+			}
+		}
+	}
+
+	for _, tree := range trees {
+		if tree.Root != nil {
+			sortChildren(tree.Root, positions)
+			tree.Root.Link()
+		}
+	}
+
+	return trees, errors.Join(errs...)
+}
 
-	return tree
+// discoveryWorker lists the projects and folders of each task until tasks is closed
+func discoveryWorker(ctx context.Context, client gcp.ResourceClient, tasks <-chan discoveryTask, results chan<- discoveryResult) {
+	for task := range tasks {
+		node, folders, err := listFolder(ctx, client, task.entry, task.depth)
+		results <- discoveryResult{task: task, node: node, folders: folders, err: err}
+	}
 }
 
-func getTree(ctx context.Context, root models.Entry, executor gcp.CommandExecutor) *models.Node {
+// listFolder returns the node of root with its projects and the folders directly below it.
+// A folder whose projects cannot be listed is left out, one whose subfolders cannot be listed is kept without them,
+// and both return the error.
+func listFolder(ctx context.Context, client gcp.ResourceClient, root models.Entry, depth int) (*models.Node, []models.Entry, error) {
 	log := logger.New(appID, "getStructure")
 	log.DebugWithExtra("getStructure", map[string]any{
-		"rootFolderId": rootFolderId,
+		"root":  root.ResourceName(),
+		"depth": depth,
 	})
-	projects, err := gcp.GetProjects(ctx, root.Id, executor)
+
+	projects, err := client.GetProjects(ctx, root)
 	if err != nil {
 		log.Error("Failed to get projects", err)
-		return nil
+		return nil, nil, fmt.Errorf("failed to list the projects of %s: %w", root.ResourceName(), err)
 	}
-
 	node := models.NewNode(&root, projects)
 
-	folders, err := gcp.GetFolders(ctx, root.Id, executor)
+	folders, err := client.GetFolders(ctx, root)
 	if err != nil {
 		log.Error("Failed to get folders", err)
-		return node
-	}
-	for _, folder := range folders {
-		node.Children = append(node.Children, getTree(ctx, folder, executor))
+		return node, nil, fmt.Errorf("failed to list the folders of %s: %w", root.ResourceName(), err)
 	}
 
-	return node
+	return node, folders, nil
 }
 
-func getTreeWithConcurrentSubfolders(ctx context.Context, root models.Entry, executor gcp.CommandExecutor) *models.Node {
-	log := logger.New(appID, "getTreeWithConcurrentSubfolders")
+func attachNode(trees []*models.Tree, positions map[*models.Node]int, task discoveryTask, node *models.Node) {
+	positions[node] = task.index
+	if task.parent == nil {
+		trees[task.tree].Root = node
+		return
+	}
+	task.parent.AddChild(node)
+}
 
-	// Get projects and folders for current folder (sequential)
-	projects, err := gcp.GetProjects(ctx, root.Id, executor)
-	if err != nil {
-		log.Error("Failed to get projects", err)
-		return nil
+// sortChildren restores the order in which the API listed the subfolders of every node
+func sortChildren(node *models.Node, positions map[*models.Node]int) {
+	slices.SortStableFunc(node.Children, func(a, b *models.Node) int {
+		return positions[a] - positions[b]
+	})
+	for _, child := range node.Children {
+		sortChildren(child, positions)
 	}
+}
 
-	folders, err := gcp.GetFolders(ctx, root.Id, executor)
-	if err != nil {
-		log.Error("Failed to get folders", err)
+// truncatedNode returns the node of a folder below --max-depth, depth is the level of root below the discovery root
+func truncatedNode(root models.Entry, depth int) *models.Node {
+	if maxDepth <= 0 || depth < maxDepth {
 		return nil
 	}
 
-	// Create node
-	node := models.NewNode(&root, projects)
+	node := models.NewNode(&root, nil)
+	node.Truncated = true
 
-	// Process subfolders concurrently (but still recursively sequential)
-	if len(folders) > 0 {
-		var wg sync.WaitGroup
-		children := make([]*models.Node, len(folders))
+	return node
+}
 
-		// Create one goroutine per subfolder
-		for i, folder := range folders {
-			wg.Add(1)
+// attachLiens records the liens of the given projects that are not already pending deletion. Liens cost one call
+// per project, so they are only fetched for the projects a run deletes, or for every project with --show-liens.
+// Liens are informational, so a failure to list them is only logged.
+func attachLiens(ctx context.Context, client gcp.ResourceClient, projects []*models.Entry) {
+	fetchEach(ctx, "liens", projects, func(project *models.Entry) error {
+		liens, err := client.GetLiens(ctx, project.Id)
+		if err != nil {
+			return err
+		}
+		project.Liens = liens
+		return nil
+	})
+}
 
-			go func(index int, folderEntry models.Entry) {
-				defer wg.Done()
+// forestProjects returns a pointer to every project of the forest that the selection does not exclude,
+// a nil selection keeps all of them
+func forestProjects(forest *models.Forest, selection models.Selection) []*models.Entry {
+	result := make([]*models.Entry, 0)
+	for _, entry := range forest.Entries() {
+		if entry.Type == models.EntryTypeProject && !selection.Excluded(*entry) {
+			result = append(result, entry)
+		}
+	}
 
-				log.DebugWithExtra("Processing subfolder", map[string]any{
-					"index":    index,
-					"folderId": folderEntry.Id,
-				})
+	return result
+}
 
-				// Recursive call (still sequential within each subtree)
-				children[index] = getTreeWithConcurrentSubfolders(ctx, folderEntry, executor)
-			}(i, folder)
+// attachTags records the effective tags of every entry that is not already pending deletion.
+// Tags cost one call per entry and are only written to snapshots, so they are fetched after discovery
+// when a snapshot is written. Like liens, tags are informational, so failures are only logged.
+func attachTags(ctx context.Context, client gcp.ResourceClient, entries []*models.Entry) {
+	fetchEach(ctx, "tags", entries, func(entry *models.Entry) error {
+		tags, err := client.GetTags(ctx, *entry)
+		if err != nil {
+			return err
 		}
+		entry.Tags = tags
+		return nil
+	})
+}
 
-		wg.Wait()
+// fetchEach calls fetch for every entry not pending deletion with --discovery-workers workers, each entry is
+// only touched by one of them. Failures are logged once per run rather than once per entry, and the remaining
+// calls are skipped after a permission or authentication error since they would fail the same way.
+func fetchEach(ctx context.Context, what string, entries []*models.Entry, fetch func(entry *models.Entry) error) {
+	log := logger.New(appID, "fetchEach")
 
-		// Add non-nil children
-		for _, child := range children {
-			if child != nil {
-				node.Children = append(node.Children, child)
+	pending := make(chan *models.Entry)
+	var mutex sync.Mutex
+	var errs []error
+	var denied atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount(discoveryWorkers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range pending {
+				if denied.Load() {
+					continue
+				}
+				err := fetch(entry)
+				if err == nil {
+					continue
+				}
+				if errors.Is(err, apperrors.ErrPermissionDenied) || errors.Is(err, apperrors.ErrUnauthenticated) {
+					denied.Store(true)
+				}
+				mutex.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", entry.ResourceName(), err))
+				mutex.Unlock()
 			}
+		}()
+	}
+	for _, entry := range entries {
+		if !entry.PendingDeletion() {
+			pending <- entry
 		}
 	}
+	close(pending)
+	wg.Wait()
 
-	return node
+	if len(errs) > 0 {
+		log.Warn(fmt.Sprintf("Failed to list the %s of %d resources, they are left empty: %v", what, len(errs), errs[0]))
+	}
 }
@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/gcp"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// benchClient serves a generated hierarchy with a fixed latency per call. Like ConcurrentExecutor it lets only
+// a limited number of calls run at once, and it records the peak number of goroutines seen during discovery.
+type benchClient struct {
+	gcp.ResourceClient
+
+	fanout    int
+	levels    int
+	latency   time.Duration
+	semaphore chan struct{}
+	peak      atomic.Int64
+}
+
+func newBenchClient(fanout, levels, maxConcurrent int) *benchClient {
+	return &benchClient{fanout: fanout, levels: levels, latency: 200 * time.Microsecond, semaphore: make(chan struct{}, maxConcurrent)}
+}
+
+func (c *benchClient) call() {
+	if current := int64(runtime.NumGoroutine()); current > c.peak.Load() {
+		c.peak.Store(current)
+	}
+	c.semaphore <- struct{}{}
+	time.Sleep(c.latency)
+	<-c.semaphore
+}
+
+func (c *benchClient) GetProjects(_ context.Context, parent models.Entry) ([]models.Entry, error) {
+	c.call()
+	return []models.Entry{*models.NewEntry(parent.Id+"-p", "Project "+parent.Id, models.EntryTypeProject)}, nil
+}
+
+func (c *benchClient) GetFolders(_ context.Context, parent models.Entry) ([]models.Entry, error) {
+	c.call()
+	if len(parent.Id)/2 >= c.levels {
+		return nil, nil
+	}
+	folders := make([]models.Entry, 0, c.fanout)
+	for i := 0; i < c.fanout; i++ {
+		folders = append(folders, *models.NewEntry(fmt.Sprintf("%s%02d", parent.Id, i), "Folder", models.EntryTypeFolder))
+	}
+	return folders, nil
+}
+
+// goroutinePerFolder is the previous discovery, which started a goroutine for every subfolder at every level
+func goroutinePerFolder(ctx context.Context, root models.Entry, client gcp.ResourceClient) *models.Node {
+	projects, _ := client.GetProjects(ctx, root)
+	folders, _ := client.GetFolders(ctx, root)
+	node := models.NewNode(&root, projects)
+
+	var wg sync.WaitGroup
+	children := make([]*models.Node, len(folders))
+	for i, folder := range folders {
+		wg.Add(1)
+		go func(index int, folderEntry models.Entry) {
+			defer wg.Done()
+			children[index] = goroutinePerFolder(ctx, folderEntry, client)
+		}(i, folder)
+	}
+	wg.Wait()
+	for _, child := range children {
+		node.AddChild(child)
+	}
+
+	return node
+}
+
+func TestDiscover_PreservesOrder(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	client := newBenchClient(3, 2, 4)
+	client.latency = 0
+	roots := []models.Entry{*models.NewEntry("r", "Root", models.EntryTypeFolder)}
+
+	expected := goroutinePerFolder(context.Background(), roots[0], client)
+	trees, err := discover(context.Background(), roots, client, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(trees) != 1 || trees[0].Root == nil {
+		t.Fatalf("Expected 1 tree, got %v", trees)
+	}
+
+	expectedOrder := models.NewForest(&models.Tree{Root: expected}).PostOrderTraversal()
+	actualOrder := models.NewForest(trees[0]).PostOrderTraversal()
+	if len(actualOrder) != len(expectedOrder) {
+		t.Fatalf("Expected %d entries, got %d", len(expectedOrder), len(actualOrder))
+	}
+	for i := range expectedOrder {
+		if actualOrder[i].ResourceName() != expectedOrder[i].ResourceName() {
+			t.Errorf("Expected entry %d to be %s, got %s", i, expectedOrder[i].ResourceName(), actualOrder[i].ResourceName())
+		}
+	}
+
+	if len(actualOrder[0].Ancestry) != 3 {
+		t.Errorf("Expected the deepest project to have 3 ancestors, got %+v", actualOrder[0].Ancestry)
+	}
+}
+
+// failingClient fails to list the folders below one folder of the bench hierarchy
+type failingClient struct {
+	*benchClient
+	failing string
+}
+
+func (c *failingClient) GetFolders(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	if parent.Id == c.failing {
+		return nil, errors.New("gcloud: executable file not found in $PATH")
+	}
+	return c.benchClient.GetFolders(ctx, parent)
+}
+
+func TestDiscover_ReturnsListingErrors(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	bench := newBenchClient(2, 2, 4)
+	bench.latency = 0
+	roots := []models.Entry{*models.NewEntry("r", "Root", models.EntryTypeFolder)}
+
+	tests := []struct {
+		name    string
+		failing string
+	}{
+		{"root", "r"},
+		{"subfolder", "r01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discover(context.Background(), roots, &failingClient{benchClient: bench, failing: tt.failing}, 2)
+			if err == nil || !strings.Contains(err.Error(), "folders/"+tt.failing) {
+				t.Errorf("Expected an error naming folders/%s, got %v", tt.failing, err)
+			}
+		})
+	}
+}
+
+// taggingClient tags every entry of the bench hierarchy with its own id, or fails with err
+type taggingClient struct {
+	*benchClient
+	err   error
+	calls atomic.Int64
+}
+
+func (c *taggingClient) GetTags(_ context.Context, entry models.Entry) (map[string]string, error) {
+	c.calls.Add(1)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return map[string]string{"123/id": entry.Id}, nil
+}
+
+func TestAttachTags(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	bench := newBenchClient(2, 1, 4)
+	bench.latency = 0
+	roots := []models.Entry{*models.NewEntry("r", "Root", models.EntryTypeFolder)}
+
+	// The bench client does not implement GetTags, discovery itself must not fetch tags
+	trees, err := discover(context.Background(), roots, bench, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	forest := models.NewForest(trees[0])
+
+	client := &taggingClient{benchClient: bench}
+	attachTags(context.Background(), client, forest.Entries())
+
+	entries := forest.PostOrderTraversal()
+	if len(entries) != 6 {
+		t.Fatalf("Expected 6 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Tags["123/id"] != entry.Id {
+			t.Errorf("Expected %s to be tagged with its id, got %v", entry.ResourceName(), entry.Tags)
+		}
+	}
+}
+
+func TestAttachTags_StopsAfterPermissionDenied(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	entries := []*models.Entry{
+		models.NewEntry("a", "A", models.EntryTypeProject),
+		models.NewEntry("b", "B", models.EntryTypeProject),
+		models.NewEntry("c", "C", models.EntryTypeProject),
+	}
+
+	client := &taggingClient{err: apperrors.ErrPermissionDenied}
+	attachTags(context.Background(), client, entries)
+
+	if calls := client.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 call with a single worker, got %d", calls)
+	}
+	for _, entry := range entries {
+		if entry.Tags != nil {
+			t.Errorf("Expected no tags on %s, got %v", entry.Id, entry.Tags)
+		}
+	}
+}
+
+func TestAttachLiens_SelectedProjectsOnly(t *testing.T) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	pending := *models.NewEntry("pending", "Pending", models.EntryTypeProject)
+	pending.LifecycleState = models.LifecycleStateDeleteRequested
+	root := models.NewNode(models.NewEntry("r", "Root", models.EntryTypeFolder), []models.Entry{
+		*models.NewEntry("selected", "Selected", models.EntryTypeProject),
+		*models.NewEntry("excluded", "Excluded", models.EntryTypeProject),
+		pending,
+	})
+	forest := models.NewForest(&models.Tree{Root: root})
+	selection := models.Selection{"projects/excluded": {Excluded: true}}
+
+	attachLiens(context.Background(), &lienClient{}, forestProjects(forest, selection))
+
+	for _, project := range root.Values {
+		if listed := len(project.Liens) > 0; listed != (project.Id == "selected") {
+			t.Errorf("Expected only the selected project to have its liens listed, got %s with %v", project.Id, project.Liens)
+		}
+	}
+}
+
+// BenchmarkDiscovery compares both designs on a hierarchy of 1+10+100+1000 folders with 8 calls in flight.
+// The worker pool keeps the goroutine count at the number of workers, the previous design starts one per folder.
+func BenchmarkDiscovery(b *testing.B) {
+	logger.Init(logger.Config{Level: "error", Source: appID, Format: "json"})
+	roots := []models.Entry{*models.NewEntry("r", "Root", models.EntryTypeFolder)}
+
+	b.Run("goroutine-per-folder", func(b *testing.B) {
+		client := newBenchClient(10, 3, 8)
+		for i := 0; i < b.N; i++ {
+			goroutinePerFolder(context.Background(), roots[0], client)
+		}
+		b.ReportMetric(float64(client.peak.Load()), "peak-goroutines")
+	})
+
+	for _, workers := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("worker-pool-%d", workers), func(b *testing.B) {
+			client := newBenchClient(10, 3, 8)
+			for i := 0; i < b.N; i++ {
+				discover(context.Background(), roots, client, workers)
+			}
+			b.ReportMetric(float64(client.peak.Load()), "peak-goroutines")
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"sync"
@@ -28,11 +29,17 @@ func main() {
 		close(errCh)
 	}()
 
+	var err error
 	select {
 	case <-c:
-	case <-errCh:
+		// Let the command stop and record what it did before exiting
+		cancelFunc()
+		err = errors.Join(context.Canceled, <-errCh)
+	case err = <-errCh:
 	}
 
 	cancelFunc()
 	wg.Wait()
+
+	os.Exit(app.ExitCode(err))
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BackupVersion is the backup format written by this version of the tool
+const BackupVersion = 1
+
+// BillingInfo is the billing linkage of a project
+type BillingInfo struct {
+	// AccountName is the linked billing account, e.g. billingAccounts/012345-567890-ABCDEF
+	AccountName string `json:"billingAccountName,omitempty"`
+	Enabled     bool   `json:"billingEnabled"`
+}
+
+// ProjectBackup holds what is needed to prove a project existed and to rebuild it.
+// The labels and the parent are part of the project entry.
+type ProjectBackup struct {
+	Version         int             `json:"version"`
+	BackedUpAt      time.Time       `json:"backedUpAt"`
+	Project         Entry           `json:"project"`
+	IAMPolicy       json.RawMessage `json:"iamPolicy"`
+	EnabledServices []string        `json:"enabledServices"`
+	Billing         BillingInfo     `json:"billing"`
+}
+
+// BackupIndex lists the project backups taken during a single run
+type BackupIndex struct {
+	Version   int                `json:"version"`
+	StartedAt time.Time          `json:"startedAt"`
+	Projects  []BackupIndexEntry `json:"projects"`
+}
+
+// BackupIndexEntry points to the backup file of a single project
+type BackupIndexEntry struct {
+	ProjectId string `json:"projectId"`
+	Name      string `json:"name"`
+	Parent    string `json:"parent,omitempty"`
+	// File is the backup file name relative to the index
+	File string `json:"file"`
+}
@@ -0,0 +1,88 @@
+package models
+
+// DeletionGraph tracks which entries of a deletion can run. A folder depends on every entry of the
+// deletion directly below it and is released as soon as the last of them finished.
+// It is not safe for concurrent use, the scheduler owning it has to serialize calls.
+type DeletionGraph struct {
+	entries []Entry
+	// parents maps the resource name of an entry to its parent, when the parent is part of the deletion
+	parents map[string]string
+	// waiting counts the unfinished children of every folder
+	waiting map[string]int
+	folders map[string]Entry
+	// blocked maps a folder that can no longer be deleted to the failed entry below it
+	blocked map[string]Entry
+}
+
+func NewDeletionGraph(entries []Entry) *DeletionGraph {
+	graph := &DeletionGraph{
+		entries: entries,
+		parents: make(map[string]string),
+		waiting: make(map[string]int),
+		folders: make(map[string]Entry),
+		blocked: make(map[string]Entry),
+	}
+	for _, entry := range entries {
+		if entry.Type == EntryTypeFolder {
+			graph.folders[entry.ResourceName()] = entry
+		}
+	}
+	for _, entry := range entries {
+		parent := entry.ParentResourceName()
+		if _, found := graph.folders[parent]; found {
+			graph.parents[entry.ResourceName()] = parent
+			graph.waiting[parent]++
+		}
+	}
+
+	return graph
+}
+
+// Ready returns the entries that can run right away, preserving their order
+func (g *DeletionGraph) Ready() []Entry {
+	result := make([]Entry, 0)
+	for _, entry := range g.entries {
+		if g.waiting[entry.ResourceName()] == 0 {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// Done marks the entry as deleted and returns the folder it released, if any. A blocked folder is never released.
+func (g *DeletionGraph) Done(entry Entry) (Entry, bool) {
+	parent, found := g.parents[entry.ResourceName()]
+	if !found {
+		return Entry{}, false
+	}
+
+	g.waiting[parent]--
+	if g.waiting[parent] > 0 || g.IsBlocked(g.folders[parent]) {
+		return Entry{}, false
+	}
+
+	return g.folders[parent], true
+}
+
+// Fail marks the deletion of the entry as failed and blocks every folder above it, since they can no longer
+// become empty. It returns the folders it newly blocked, nearest first.
+func (g *DeletionGraph) Fail(entry Entry) []Entry {
+	result := make([]Entry, 0)
+	parent, found := g.parents[entry.ResourceName()]
+	for found {
+		if _, blocked := g.blocked[parent]; blocked {
+			break
+		}
+		g.blocked[parent] = entry
+		result = append(result, g.folders[parent])
+		parent, found = g.parents[parent]
+	}
+
+	return result
+}
+
+func (g *DeletionGraph) IsBlocked(entry Entry) bool {
+	_, found := g.blocked[entry.ResourceName()]
+	return found
+}
@@ -0,0 +1,117 @@
+package models
+
+import "testing"
+
+// newGraphTestForest builds root > {a > {a1, pa}, b > {pb}} plus the project pr directly in root
+func newGraphTestForest() *Forest {
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), []Entry{*NewEntry("pr", "PR", EntryTypeProject)})
+	a := NewNode(NewEntry("a", "A", EntryTypeFolder), []Entry{*NewEntry("pa", "PA", EntryTypeProject)})
+	a.AddChild(NewNode(NewEntry("a1", "A1", EntryTypeFolder), nil))
+	b := NewNode(NewEntry("b", "B", EntryTypeFolder), []Entry{*NewEntry("pb", "PB", EntryTypeProject)})
+	root.AddChild(a)
+	root.AddChild(b)
+	root.Link()
+
+	return NewForest(&Tree{Root: root})
+}
+
+func ids(entries []Entry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Id)
+	}
+
+	return result
+}
+
+func TestDeletionGraph(t *testing.T) {
+	entries := newGraphTestForest().PostOrderTraversal()
+	byId := make(map[string]Entry)
+	for _, entry := range entries {
+		byId[entry.Id] = entry
+	}
+
+	graph := NewDeletionGraph(entries)
+
+	ready := ids(graph.Ready())
+	expected := map[string]bool{"a1": true, "pa": true, "pb": true, "pr": true}
+	if len(ready) != len(expected) {
+		t.Fatalf("Expected ready entries %v, got %v", expected, ready)
+	}
+	for _, id := range ready {
+		if !expected[id] {
+			t.Errorf("Unexpected ready entry %s", id)
+		}
+	}
+
+	steps := []struct {
+		done     string
+		released string
+	}{
+		{"pb", "b"},
+		{"a1", ""},
+		{"pa", "a"},
+		{"a", ""},
+		{"b", ""},
+		{"pr", "root"},
+	}
+	for _, step := range steps {
+		released, found := graph.Done(byId[step.done])
+		if step.released == "" && found {
+			t.Errorf("Expected finishing %s to release nothing, got %s", step.done, released.Id)
+		}
+		if step.released != "" && (!found || released.Id != step.released) {
+			t.Errorf("Expected finishing %s to release %s, got %v", step.done, step.released, released.Id)
+		}
+	}
+}
+
+func TestDeletionGraph_PartialSelection(t *testing.T) {
+	entries := newGraphTestForest().PostOrderTraversal()
+	// Only b and its project are deleted, root is kept
+	selected := make([]Entry, 0)
+	for _, entry := range entries {
+		if entry.Id == "b" || entry.Id == "pb" {
+			selected = append(selected, entry)
+		}
+	}
+
+	graph := NewDeletionGraph(selected)
+
+	if ready := ids(graph.Ready()); len(ready) != 1 || ready[0] != "pb" {
+		t.Fatalf("Expected only pb to be ready, got %v", ready)
+	}
+
+	if released, found := graph.Done(selected[0]); !found || released.Id != "b" {
+		t.Errorf("Expected b to be released, got %v", released.Id)
+	}
+
+	if _, found := graph.Done(selected[1]); found {
+		t.Error("Expected b to release nothing since root is not deleted")
+	}
+}
+
+func TestDeletionGraph_Fail(t *testing.T) {
+	entries := newGraphTestForest().PostOrderTraversal()
+	byId := make(map[string]Entry)
+	for _, entry := range entries {
+		byId[entry.Id] = entry
+	}
+
+	graph := NewDeletionGraph(entries)
+
+	if blocked := ids(graph.Fail(byId["a1"])); len(blocked) != 2 || blocked[0] != "a" || blocked[1] != "root" {
+		t.Errorf("Expected a1 to block a and root, got %v", blocked)
+	}
+	if released, found := graph.Done(byId["pa"]); found {
+		t.Errorf("Expected the blocked folder a not to be released, got %s", released.Id)
+	}
+	if !graph.IsBlocked(byId["root"]) || graph.IsBlocked(byId["b"]) {
+		t.Error("Expected root to be blocked and b not")
+	}
+
+	// root is already blocked by a1, so pb only blocks b
+	if blocked := ids(graph.Fail(byId["pb"])); len(blocked) != 1 || blocked[0] != "b" {
+		t.Errorf("Expected pb to block only b, got %v", blocked)
+	}
+}
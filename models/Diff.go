@@ -0,0 +1,239 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xlab/treeprint"
+)
+
+// ChangeKind describes how a folder or project differs between two trees
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeMoved   ChangeKind = "moved"
+	ChangeRenamed ChangeKind = "renamed"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// Change is a single difference between two trees. From and To hold the previous and
+// current parent resource name of a move, or the previous and current name of a rename.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	Entry Entry      `json:"entry"`
+	From  string     `json:"from,omitempty"`
+	To    string     `json:"to,omitempty"`
+}
+
+// Diff holds the changes between a before and an after forest
+type Diff struct {
+	Changes []Change `json:"changes"`
+
+	before *Forest
+	after  *Forest
+}
+
+// diffItem is an indexed folder or project along with the resource name of its parent
+type diffItem struct {
+	entry  Entry
+	parent string
+	node   *Node
+}
+
+// Diff returns the changes that turn t into after
+func (t *Tree) Diff(after *Tree) *Diff {
+	return NewForest(t).Diff(NewForest(after))
+}
+
+// Diff returns the changes that turn f into after. Entries are matched by type and id,
+// an entry present in both forests can be moved and renamed at the same time.
+// Entries pending deletion count as absent, so a deletion shows as removed before the entry is purged.
+func (f *Forest) Diff(after *Forest) *Diff {
+	beforeItems, beforeOrder := f.index()
+	afterItems, afterOrder := after.index()
+
+	diff := &Diff{Changes: make([]Change, 0), before: f, after: after}
+	for _, key := range afterOrder {
+		current := afterItems[key]
+		previous, found := beforeItems[key]
+		if !found {
+			diff.Changes = append(diff.Changes, Change{Kind: ChangeAdded, Entry: current.entry})
+			continue
+		}
+		if previous.parent != "" && current.parent != "" && previous.parent != current.parent {
+			diff.Changes = append(diff.Changes, Change{Kind: ChangeMoved, Entry: current.entry, From: previous.parent, To: current.parent})
+		}
+		if previous.entry.Name != current.entry.Name {
+			diff.Changes = append(diff.Changes, Change{Kind: ChangeRenamed, Entry: current.entry, From: previous.entry.Name, To: current.entry.Name})
+		}
+	}
+
+	for _, key := range beforeOrder {
+		if _, found := afterItems[key]; !found {
+			diff.Changes = append(diff.Changes, Change{Kind: ChangeRemoved, Entry: beforeItems[key].entry})
+		}
+	}
+
+	return diff
+}
+
+// Count returns the number of changes of the given kind
+func (d *Diff) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range d.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Print renders the after forest with added entries in green, removed entries in red under
+// their previous parent, and moved or renamed entries in yellow
+func (d *Diff) Print() {
+	changes := make(map[string][]Change)
+	for _, change := range d.Changes {
+		key := change.Entry.ResourceName()
+		changes[key] = append(changes[key], change)
+	}
+
+	beforeItems, _ := d.before.index()
+	afterItems, _ := d.after.index()
+
+	// Removed entries are attached to their previous parent. Entries whose parent was removed as
+	// well are rendered as part of that subtree, removed entries without a known parent on their own.
+	removed := make(map[string][]diffItem)
+	var removedRoots []diffItem
+	for _, change := range d.Changes {
+		if change.Kind != ChangeRemoved {
+			continue
+		}
+		item := beforeItems[change.Entry.ResourceName()]
+		_, parentBefore := beforeItems[item.parent]
+		_, parentAfter := afterItems[item.parent]
+		switch {
+		case parentAfter:
+			removed[item.parent] = append(removed[item.parent], item)
+		case !parentBefore && item.node != nil:
+			removedRoots = append(removedRoots, item)
+		}
+	}
+
+	for _, tree := range d.after.Trees {
+		root := treeprint.New()
+		printDiffNode(root, tree.Root, changes, removed)
+		fmt.Println(root.String())
+	}
+	for _, item := range removedRoots {
+		root := treeprint.New()
+		printRemovedNode(root, item.node)
+		fmt.Println(root.String())
+	}
+
+	fmt.Printf("%d added, %d removed, %d moved, %d renamed\n",
+		d.Count(ChangeAdded), d.Count(ChangeRemoved), d.Count(ChangeMoved), d.Count(ChangeRenamed))
+}
+
+func printDiffNode(branch treeprint.Tree, node *Node, changes map[string][]Change, removed map[string][]diffItem) {
+	key := node.Current.ResourceName()
+	folder := branch.AddBranch(diffLabel(*node.Current, changes[key]))
+	for _, value := range node.Values {
+		if !value.PendingDeletion() {
+			folder.AddNode(diffLabel(value, changes[value.ResourceName()]))
+		}
+	}
+	for _, child := range node.Children {
+		if !child.Current.PendingDeletion() {
+			printDiffNode(folder, child, changes, removed)
+		}
+	}
+	for _, item := range removed[key] {
+		if item.node != nil {
+			printRemovedNode(folder, item.node)
+		} else {
+			folder.AddNode(colorRed + "- " + entryLabel(item.entry) + colorReset)
+		}
+	}
+}
+
+// printRemovedNode renders a whole removed subtree
+func printRemovedNode(branch treeprint.Tree, node *Node) {
+	folder := branch.AddBranch(colorRed + "- " + entryLabel(*node.Current) + colorReset)
+	for _, value := range node.Values {
+		folder.AddNode(colorRed + "- " + entryLabel(value) + colorReset)
+	}
+	for _, child := range node.Children {
+		printRemovedNode(folder, child)
+	}
+}
+
+func diffLabel(entry Entry, changes []Change) string {
+	if len(changes) == 0 {
+		return entryLabel(entry)
+	}
+	if changes[0].Kind == ChangeAdded {
+		return colorGreen + "+ " + entryLabel(entry) + colorReset
+	}
+
+	notes := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change.Kind {
+		case ChangeMoved:
+			notes = append(notes, "moved from "+change.From)
+		case ChangeRenamed:
+			notes = append(notes, fmt.Sprintf("renamed from %q", change.From))
+		}
+	}
+
+	return colorYellow + "~ " + entryLabel(entry) + " [" + strings.Join(notes, ", ") + "]" + colorReset
+}
+
+func entryLabel(entry Entry) string {
+	return fmt.Sprintf("%s (%s)", entry.Name, entry.Id)
+}
+
+// index maps the resource name of every folder, project and organization of the forest to
+// its item, and returns the resource names in pre order. Entries pending deletion are left out.
+func (f *Forest) index() (map[string]diffItem, []string) {
+	items := make(map[string]diffItem)
+	var order []string
+	for _, tree := range f.Trees {
+		order = indexNode(items, order, tree.Root)
+	}
+
+	return items, order
+}
+
+func indexNode(items map[string]diffItem, order []string, node *Node) []string {
+	key := node.Current.ResourceName()
+	parent := node.Current.Parent
+	if node.Parent != nil {
+		parent = node.Parent.Current.ResourceName()
+	}
+	if _, found := items[key]; !found && !node.Current.PendingDeletion() {
+		items[key] = diffItem{entry: *node.Current, parent: parent, node: node}
+		order = append(order, key)
+	}
+
+	for _, value := range node.Values {
+		valueKey := value.ResourceName()
+		if _, found := items[valueKey]; !found && !value.PendingDeletion() {
+			items[valueKey] = diffItem{entry: value, parent: key}
+			order = append(order, valueKey)
+		}
+	}
+	for _, child := range node.Children {
+		order = indexNode(items, order, child)
+	}
+
+	return order
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+)
+
+func newDiffTestTree(projects map[string][]string, renamed string) *Tree {
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), nil)
+	for _, folderId := range []string{"a", "b"} {
+		ids, found := projects[folderId]
+		if !found {
+			continue
+		}
+		name := "Folder " + folderId
+		if folderId == renamed {
+			name = "Renamed " + folderId
+		}
+		values := make([]Entry, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, *NewEntry(id, id, EntryTypeProject))
+		}
+		root.AddChild(NewNode(NewEntry(folderId, name, EntryTypeFolder), values))
+	}
+
+	return newTestTree(root)
+}
+
+func TestTree_Diff(t *testing.T) {
+	before := newDiffTestTree(map[string][]string{"a": {"p1", "p2"}, "b": {"p3"}}, "")
+	after := newDiffTestTree(map[string][]string{"a": {"p1", "p4"}, "b": {"p2"}}, "a")
+
+	diff := before.Diff(after)
+
+	expected := []struct {
+		kind ChangeKind
+		id   string
+		from string
+		to   string
+	}{
+		{kind: ChangeRenamed, id: "a", from: "Folder a", to: "Renamed a"},
+		{kind: ChangeAdded, id: "p4"},
+		{kind: ChangeMoved, id: "p2", from: "folders/a", to: "folders/b"},
+		{kind: ChangeRemoved, id: "p3"},
+	}
+
+	if len(diff.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), diff.Changes)
+	}
+
+	for i, change := range diff.Changes {
+		if change.Kind != expected[i].kind || change.Entry.Id != expected[i].id || change.From != expected[i].from || change.To != expected[i].to {
+			t.Errorf("Change %d: expected %+v, got %s %s %s -> %s", i, expected[i], change.Kind, change.Entry.Id, change.From, change.To)
+		}
+	}
+
+	if diff.Count(ChangeRemoved) != 1 {
+		t.Errorf("Expected 1 removal, got %d", diff.Count(ChangeRemoved))
+	}
+}
+
+func TestForest_Diff_RemovedSubtree(t *testing.T) {
+	before := newDiffTestTree(map[string][]string{"a": {"p1"}, "b": {"p2"}}, "")
+	after := newDiffTestTree(map[string][]string{"a": {"p1"}}, "")
+
+	diff := NewForest(before).Diff(NewForest(after))
+
+	expected := []string{"b", "p2"}
+	if len(diff.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), diff.Changes)
+	}
+	for i, change := range diff.Changes {
+		if change.Kind != ChangeRemoved || change.Entry.Id != expected[i] {
+			t.Errorf("Change %d: expected removal of %s, got %s %s", i, expected[i], change.Kind, change.Entry.Id)
+		}
+	}
+}
+
+func TestForest_Diff_PendingDeletion(t *testing.T) {
+	before := newDiffTestTree(map[string][]string{"a": {"p1", "p2"}, "b": {"p3"}}, "")
+	after := newDiffTestTree(map[string][]string{"a": {"p1", "p2"}, "b": {"p3"}}, "")
+	// A cleanup deleted p2 and the whole of b, discovery still lists them pending deletion
+	for _, node := range after.Root.Children {
+		for i := range node.Values {
+			if node.Values[i].Id != "p1" {
+				node.Values[i].LifecycleState = LifecycleStateDeleteRequested
+			}
+		}
+		if node.Current.Id == "b" {
+			node.Current.LifecycleState = LifecycleStateDeleteRequested
+		}
+	}
+
+	diff := NewForest(before).Diff(NewForest(after))
+
+	expected := []string{"p2", "b", "p3"}
+	if len(diff.Changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), diff.Changes)
+	}
+	for i, change := range diff.Changes {
+		if change.Kind != ChangeRemoved || change.Entry.Id != expected[i] {
+			t.Errorf("Change %d: expected removal of %s, got %s %s", i, expected[i], change.Kind, change.Entry.Id)
+		}
+	}
+
+	// Comparing the pending entries with themselves shows no change
+	if changes := NewForest(after).Diff(NewForest(after)).Changes; len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+func TestForest_Diff_Identical(t *testing.T) {
+	tree := newDiffTestTree(map[string][]string{"a": {"p1"}, "b": {"p2"}}, "")
+
+	if diff := tree.Diff(tree); len(diff.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", diff.Changes)
+	}
+}
@@ -1,21 +1,95 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type EntryType int
 
+const (
+	LifecycleStateActive          = "ACTIVE"
+	LifecycleStateDeleteRequested = "DELETE_REQUESTED"
+)
+
+// PurgeWindow is how long a project stays in DELETE_REQUESTED before it is purged for good
+const PurgeWindow = 30 * 24 * time.Hour
+
 const (
 	EntryTypeProject EntryType = iota
 	EntryTypeFolder
+	EntryTypeOrganization
 )
 
 type Entry struct {
-	Type EntryType
-	Id   string
-	Name string
+	Type EntryType `json:"type" yaml:"type"`
+	Id   string    `json:"id" yaml:"id"`
+	Name string    `json:"name" yaml:"name"`
+	// Number is the numeric project number, empty for folders
+	Number string `json:"number,omitempty" yaml:"number,omitempty"`
+	// Parent is the resource name of the parent, e.g. folders/123 or organizations/456
+	Parent         string    `json:"parent,omitempty" yaml:"parent,omitempty"`
+	LifecycleState string    `json:"lifecycleState,omitempty" yaml:"lifecycleState,omitempty"`
+	CreateTime     time.Time `json:"createTime,omitzero" yaml:"createTime,omitempty"`
+	// DeleteTime is when deletion was requested, only reported by the api backend
+	DeleteTime time.Time         `json:"deleteTime,omitzero" yaml:"deleteTime,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Tags holds the tag bindings of the resource keyed by namespaced tag key
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Liens lists the liens that prevent the deletion of a project
+	Liens []Lien `json:"liens,omitempty" yaml:"liens,omitempty"`
+	// Ancestry lists the known ancestors, from the top-most one down to the direct parent
+	Ancestry []Ancestor `json:"ancestry,omitempty" yaml:"ancestry,omitempty"`
+}
+
+// Ancestor identifies one level of an entry's ancestry
+type Ancestor struct {
+	Type EntryType `json:"type" yaml:"type"`
+	Id   string    `json:"id" yaml:"id"`
+	Name string    `json:"name" yaml:"name"`
+}
+
+// Lien is a Resource Manager lien placed on a project, usually by a service integration
+type Lien struct {
+	// Name is the resource name of the lien, e.g. liens/p123-abc
+	Name         string   `json:"name" yaml:"name"`
+	Reason       string   `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Origin       string   `json:"origin,omitempty" yaml:"origin,omitempty"`
+	Restrictions []string `json:"restrictions,omitempty" yaml:"restrictions,omitempty"`
 }
 
 var EntryTypes = map[EntryType]string{
-	EntryTypeProject: "project",
-	EntryTypeFolder:  "folder",
+	EntryTypeProject:      "project",
+	EntryTypeFolder:       "folder",
+	EntryTypeOrganization: "organization",
+}
+
+// String returns the human readable name of the entry type
+func (t EntryType) String() string {
+	if name, ok := EntryTypes[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("EntryType(%d)", int(t))
+}
+
+// MarshalText encodes the entry type by name, keeping snapshots readable
+func (t EntryType) MarshalText() ([]byte, error) {
+	if _, ok := EntryTypes[t]; !ok {
+		return nil, fmt.Errorf("unknown entry type %d", int(t))
+	}
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes an entry type encoded by MarshalText
+func (t *EntryType) UnmarshalText(text []byte) error {
+	for entryType, name := range EntryTypes {
+		if name == string(text) {
+			*t = entryType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown entry type %q", string(text))
 }
 
 func NewEntry(id, name string, entryType EntryType) *Entry {
@@ -25,3 +99,55 @@ func NewEntry(id, name string, entryType EntryType) *Entry {
 		Id:   id,
 	}
 }
+
+// Path returns the display names of the ancestry and the entry itself joined by slashes
+func (e *Entry) Path() string {
+	names := make([]string, 0, len(e.Ancestry)+1)
+	for _, ancestor := range e.Ancestry {
+		names = append(names, ancestor.Name)
+	}
+	names = append(names, e.Name)
+
+	return strings.Join(names, "/")
+}
+
+// ParentResourceName returns the resource name of the direct parent, taken from the ancestry when it is known
+func (e *Entry) ParentResourceName() string {
+	if len(e.Ancestry) == 0 {
+		return e.Parent
+	}
+	parent := e.Ancestry[len(e.Ancestry)-1]
+
+	return NewEntry(parent.Id, parent.Name, parent.Type).ResourceName()
+}
+
+// ResourceName returns the Resource Manager name of the entry, e.g. folders/123 or organizations/456
+func (e *Entry) ResourceName() string {
+	switch e.Type {
+	case EntryTypeOrganization:
+		return "organizations/" + e.Id
+	case EntryTypeFolder:
+		return "folders/" + e.Id
+	default:
+		return "projects/" + e.Id
+	}
+}
+
+// PendingDeletion reports whether deletion was already requested for the entry
+func (e *Entry) PendingDeletion() bool {
+	return e.LifecycleState == LifecycleStateDeleteRequested
+}
+
+// PurgeTime returns when a pending deletion becomes permanent, false when the deletion time is unknown
+func (e *Entry) PurgeTime() (time.Time, bool) {
+	if !e.PendingDeletion() || e.DeleteTime.IsZero() {
+		return time.Time{}, false
+	}
+
+	return e.DeleteTime.Add(PurgeWindow), true
+}
+
+// AsAncestor returns the reference used to list this entry in the ancestry of its descendants
+func (e *Entry) AsAncestor() Ancestor {
+	return Ancestor{Type: e.Type, Id: e.Id, Name: e.Name}
+}
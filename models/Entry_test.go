@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewEntry(t *testing.T) {
@@ -72,6 +73,7 @@ func TestEntryTypes_Constants(t *testing.T) {
 	}{
 		{EntryTypeProject, "project"},
 		{EntryTypeFolder, "folder"},
+		{EntryTypeOrganization, "organization"},
 	}
 
 	for _, tt := range tests {
@@ -95,3 +97,104 @@ func TestEntryType_Values(t *testing.T) {
 		t.Errorf("Expected EntryTypeFolder to be 1, got %d", EntryTypeFolder)
 	}
 }
+
+func TestEntry_Path(t *testing.T) {
+	entry := NewEntry("sandbox-1", "Sandbox 1", EntryTypeProject)
+
+	if entry.Path() != "Sandbox 1" {
+		t.Errorf("Expected path of an entry without ancestry to be its name, got %s", entry.Path())
+	}
+
+	entry.Ancestry = []Ancestor{
+		{Type: EntryTypeFolder, Id: "1", Name: "Acme"},
+		{Type: EntryTypeFolder, Id: "2", Name: "Engineering"},
+	}
+
+	if entry.Path() != "Acme/Engineering/Sandbox 1" {
+		t.Errorf("Expected path Acme/Engineering/Sandbox 1, got %s", entry.Path())
+	}
+}
+
+func TestEntry_ResourceName(t *testing.T) {
+	tests := []struct {
+		entry    *Entry
+		expected string
+	}{
+		{NewEntry("123", "Folder", EntryTypeFolder), "folders/123"},
+		{NewEntry("456", "Org", EntryTypeOrganization), "organizations/456"},
+		{NewEntry("my-project", "Project", EntryTypeProject), "projects/my-project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if name := tt.entry.ResourceName(); name != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestEntryType_Text(t *testing.T) {
+	for entryType, name := range EntryTypes {
+		text, err := entryType.MarshalText()
+		if err != nil {
+			t.Fatalf("Failed to marshal %s: %v", name, err)
+		}
+		if string(text) != name {
+			t.Errorf("Expected %s, got %s", name, text)
+		}
+
+		var decoded EntryType
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", name, err)
+		}
+		if decoded != entryType {
+			t.Errorf("Expected %d, got %d", entryType, decoded)
+		}
+	}
+
+	var decoded EntryType
+	if err := decoded.UnmarshalText([]byte("bucket")); err == nil {
+		t.Error("Expected error for an unknown entry type, got nil")
+	}
+
+	if _, err := EntryType(42).MarshalText(); err == nil {
+		t.Error("Expected error when marshalling an unknown entry type, got nil")
+	}
+}
+
+func TestEntry_PurgeTime(t *testing.T) {
+	deleteTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		state   string
+		deleted time.Time
+		pending bool
+		known   bool
+	}{
+		{name: "active", state: LifecycleStateActive, deleted: deleteTime},
+		{name: "pending with delete time", state: LifecycleStateDeleteRequested, deleted: deleteTime, pending: true, known: true},
+		{name: "pending without delete time", state: LifecycleStateDeleteRequested, pending: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := NewEntry("p1", "Project 1", EntryTypeProject)
+			entry.LifecycleState = tt.state
+			entry.DeleteTime = tt.deleted
+
+			if entry.PendingDeletion() != tt.pending {
+				t.Errorf("Expected pending %v, got %v", tt.pending, entry.PendingDeletion())
+			}
+
+			purgeTime, known := entry.PurgeTime()
+			if known != tt.known {
+				t.Fatalf("Expected known %v, got %v", tt.known, known)
+			}
+			if known && !purgeTime.Equal(deleteTime.Add(PurgeWindow)) {
+				t.Errorf("Expected purge time %v, got %v", deleteTime.Add(PurgeWindow), purgeTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Forest groups the trees discovered from several roots in a single run
+type Forest struct {
+	Trees []*Tree `json:"trees" yaml:"trees"`
+}
+
+func NewForest(trees ...*Tree) *Forest {
+	return &Forest{Trees: trees}
+}
+
+// Add appends a tree to the forest, trees without a root are ignored
+func (f *Forest) Add(tree *Tree) {
+	if tree == nil || tree.Root == nil {
+		return
+	}
+	f.Trees = append(f.Trees, tree)
+}
+
+// Dedupe drops every tree whose root is already part of another tree of the forest,
+// so that overlapping roots are only printed and processed once
+func (f *Forest) Dedupe() {
+	kept := make([]*Tree, 0, len(f.Trees))
+	for i, tree := range f.Trees {
+		duplicate := false
+		for j, other := range f.Trees {
+			if i == j {
+				continue
+			}
+			// Identical roots keep the first occurrence, nested roots keep the enclosing tree
+			if other.Root.Current.Id == tree.Root.Current.Id && other.Root.Current.Type == tree.Root.Current.Type {
+				duplicate = j < i
+			} else {
+				duplicate = other.Root.Find(tree.Root.Current.Type, tree.Root.Current.Id) != nil
+			}
+			if duplicate {
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, tree)
+		}
+	}
+	f.Trees = kept
+}
+
+// PostOrderTraversal returns the post order traversal of every tree, one after another
+func (f *Forest) PostOrderTraversal() []Entry {
+	var result []Entry
+	for _, tree := range f.Trees {
+		result = append(result, tree.PostOrderTraversal(tree.Root)...)
+	}
+
+	return result
+}
+
+// Entries returns a pointer to every organization, folder and project of the forest, so fields fetched
+// after discovery can be filled in place. Folders come before their projects and subfolders.
+func (f *Forest) Entries() []*Entry {
+	var result []*Entry
+	for _, tree := range f.Trees {
+		result = appendEntries(result, tree.Root)
+	}
+
+	return result
+}
+
+func appendEntries(entries []*Entry, node *Node) []*Entry {
+	entries = append(entries, node.Current)
+	for i := range node.Values {
+		entries = append(entries, &node.Values[i])
+	}
+	for _, child := range node.Children {
+		entries = appendEntries(entries, child)
+	}
+
+	return entries
+}
+
+// Roots returns the root entry of every tree
+func (f *Forest) Roots() []Entry {
+	roots := make([]Entry, 0, len(f.Trees))
+	for _, tree := range f.Trees {
+		roots = append(roots, *tree.Root.Current)
+	}
+
+	return roots
+}
+
+// Fingerprint returns a stable hash of the structure of the forest. Any added, removed, moved, renamed,
+// relabelled or state changed folder or project changes the fingerprint.
+func (f *Forest) Fingerprint() string {
+	var lines []string
+	for _, tree := range f.Trees {
+		lines = appendFingerprintLines(lines, tree.Root, "")
+	}
+	sort.Strings(lines)
+
+	hash := sha256.New()
+	for _, line := range lines {
+		hash.Write([]byte(line))
+		hash.Write([]byte{'\n'})
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func appendFingerprintLines(lines []string, node *Node, parent string) []string {
+	current := node.Current.ResourceName()
+	lines = append(lines, fingerprintLine(*node.Current, parent))
+	for _, value := range node.Values {
+		lines = append(lines, fingerprintLine(value, current))
+	}
+	for _, child := range node.Children {
+		lines = appendFingerprintLines(lines, child, current)
+	}
+
+	return lines
+}
+
+// fingerprintLine holds everything a selection can depend on: the position, name, state, creation time and labels
+func fingerprintLine(entry Entry, parent string) string {
+	labels := make([]string, 0, len(entry.Labels))
+	for key, value := range entry.Labels {
+		labels = append(labels, fmt.Sprintf("%q=%q", key, value))
+	}
+	sort.Strings(labels)
+
+	createTime := ""
+	if !entry.CreateTime.IsZero() {
+		createTime = entry.CreateTime.UTC().Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprintf("%s\t%s\t%q\t%s\t%s\t%s", entry.ResourceName(), parent, entry.Name, entry.LifecycleState, createTime, strings.Join(labels, ","))
+}
+
+func (f *Forest) Print() {
+	f.PrintWithOptions(PrintOptions{})
+}
+
+func (f *Forest) PrintWithOptions(options PrintOptions) {
+	for _, tree := range f.Trees {
+		tree.PrintWithOptions(options)
+	}
+}
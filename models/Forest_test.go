@@ -0,0 +1,175 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTree(root *Node) *Tree {
+	tree := NewTree()
+	tree.Root = root
+	return tree
+}
+
+func TestForest_Add_IgnoresEmptyTrees(t *testing.T) {
+	forest := NewForest()
+
+	forest.Add(nil)
+	forest.Add(NewTree())
+	forest.Add(newTestTree(NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil)))
+
+	if len(forest.Trees) != 1 {
+		t.Errorf("Expected 1 tree, got %d", len(forest.Trees))
+	}
+}
+
+func TestForest_Dedupe(t *testing.T) {
+	// folder1 contains folder2, folder3 is independent and listed twice
+	folder2 := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+	folder1 := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil)
+	folder1.AddChild(folder2)
+
+	nestedFolder2 := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+	folder3 := NewNode(NewEntry("folder3", "Folder 3", EntryTypeFolder), nil)
+	folder3Again := NewNode(NewEntry("folder3", "Folder 3", EntryTypeFolder), nil)
+
+	forest := NewForest(
+		newTestTree(nestedFolder2),
+		newTestTree(folder1),
+		newTestTree(folder3),
+		newTestTree(folder3Again),
+	)
+	forest.Dedupe()
+
+	if len(forest.Trees) != 2 {
+		t.Fatalf("Expected 2 trees after dedupe, got %d", len(forest.Trees))
+	}
+
+	if forest.Trees[0].Root != folder1 {
+		t.Errorf("Expected the enclosing tree to be kept, got %s", forest.Trees[0].Root.Current.Id)
+	}
+
+	if forest.Trees[1].Root != folder3 {
+		t.Errorf("Expected the first occurrence of a duplicated root to be kept")
+	}
+}
+
+func TestForest_PostOrderTraversal(t *testing.T) {
+	folder1 := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	folder2 := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+
+	forest := NewForest(newTestTree(folder1), newTestTree(folder2))
+	result := forest.PostOrderTraversal()
+
+	expected := []string{"proj1", "folder1", "proj2", "folder2"}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(result))
+	}
+
+	for i, entry := range result {
+		if entry.Id != expected[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected[i], entry.Id)
+		}
+	}
+}
+
+func TestForest_Entries(t *testing.T) {
+	folder2 := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+	folder1 := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	folder1.AddChild(folder2)
+	forest := NewForest(newTestTree(folder1))
+
+	entries := forest.Entries()
+	expectedOrder := []string{"folder1", "proj1", "folder2", "proj2"}
+	if len(entries) != len(expectedOrder) {
+		t.Fatalf("Expected %d entries, got %d", len(expectedOrder), len(entries))
+	}
+	for i, id := range expectedOrder {
+		if entries[i].Id != id {
+			t.Errorf("Expected %s at position %d, got %s", id, i, entries[i].Id)
+		}
+	}
+
+	entries[3].Labels = map[string]string{"env": "sandbox"}
+	if folder2.Values[0].Labels["env"] != "sandbox" {
+		t.Errorf("Expected entries to point into the forest, got %+v", folder2.Values[0])
+	}
+}
+
+func TestNode_Find(t *testing.T) {
+	folder2 := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+	folder1 := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil)
+	folder1.AddChild(folder2)
+
+	if found := folder1.Find(EntryTypeFolder, "folder2"); found != folder2 {
+		t.Error("Expected to find folder2")
+	}
+
+	if found := folder1.Find(EntryTypeFolder, "missing"); found != nil {
+		t.Error("Expected nil for a missing folder")
+	}
+}
+
+func TestForest_Fingerprint(t *testing.T) {
+	newForest := func() *Forest {
+		child := NewNode(NewEntry("folder2", "Folder 2", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+		root := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+		root.AddChild(child)
+		return NewForest(newTestTree(root))
+	}
+
+	expected := newForest().Fingerprint()
+	if expected != newForest().Fingerprint() {
+		t.Fatal("Expected the fingerprint of identical forests to match")
+	}
+
+	labelled := newForest()
+	labelled.Trees[0].Root.Values[0].Labels = map[string]string{"env": "sandbox", "team": "a", "keep": "false"}
+	for i := 0; i < 10; i++ {
+		relabelled := newForest()
+		relabelled.Trees[0].Root.Values[0].Labels = map[string]string{"keep": "false", "team": "a", "env": "sandbox"}
+		if labelled.Fingerprint() != relabelled.Fingerprint() {
+			t.Fatal("Expected the fingerprint not to depend on the order of labels")
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Forest)
+	}{
+		{name: "project added", mutate: func(f *Forest) {
+			f.Trees[0].Root.Values = append(f.Trees[0].Root.Values, *NewEntry("proj3", "Project 3", EntryTypeProject))
+		}},
+		{name: "project removed", mutate: func(f *Forest) {
+			f.Trees[0].Root.Children[0].Values = nil
+		}},
+		{name: "project moved", mutate: func(f *Forest) {
+			root := f.Trees[0].Root
+			root.Children[0].Values = append(root.Children[0].Values, root.Values...)
+			root.Values = nil
+		}},
+		{name: "folder renamed", mutate: func(f *Forest) {
+			f.Trees[0].Root.Children[0].Current.Name = "Renamed"
+		}},
+		{name: "project state changed", mutate: func(f *Forest) {
+			f.Trees[0].Root.Values[0].LifecycleState = "DELETE_REQUESTED"
+		}},
+		{name: "project labelled", mutate: func(f *Forest) {
+			f.Trees[0].Root.Values[0].Labels = map[string]string{"keep": "true"}
+		}},
+		{name: "folder creation time changed", mutate: func(f *Forest) {
+			f.Trees[0].Root.Children[0].Current.CreateTime = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forest := newForest()
+			tt.mutate(forest)
+
+			if forest.Fingerprint() == expected {
+				t.Errorf("Expected fingerprint to change")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// JournalEvent is a step in the deletion of a single folder or project
+type JournalEvent string
+
+const (
+	JournalPlanned   JournalEvent = "planned"
+	JournalStarted   JournalEvent = "started"
+	JournalSucceeded JournalEvent = "succeeded"
+	JournalFailed    JournalEvent = "failed"
+)
+
+// JournalRecord is one line of a deletion journal
+type JournalRecord struct {
+	Time  time.Time    `json:"time"`
+	Event JournalEvent `json:"event"`
+	Entry Entry        `json:"entry"`
+	Error string       `json:"error,omitempty"`
+}
+
+// JournalProgress is the state of a deletion rebuilt from its journal
+type JournalProgress struct {
+	// Planned lists the planned entries in the order they were first planned
+	Planned []Entry
+	// Last holds the latest event of every entry, keyed by resource name
+	Last map[string]JournalEvent
+}
+
+// ReplayJournal rebuilds the progress of a deletion from its records, later records win
+func ReplayJournal(records []JournalRecord) *JournalProgress {
+	progress := &JournalProgress{Planned: make([]Entry, 0), Last: make(map[string]JournalEvent)}
+	for _, record := range records {
+		key := record.Entry.ResourceName()
+		if _, found := progress.Last[key]; !found && record.Event == JournalPlanned {
+			progress.Planned = append(progress.Planned, record.Entry)
+		}
+		progress.Last[key] = record.Event
+	}
+
+	return progress
+}
+
+// Remaining returns the planned entries that were not deleted yet, preserving the deletion order
+func (p *JournalProgress) Remaining() []Entry {
+	result := make([]Entry, 0, len(p.Planned))
+	for _, entry := range p.Planned {
+		if p.Last[entry.ResourceName()] != JournalSucceeded {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
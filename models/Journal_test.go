@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestReplayJournal(t *testing.T) {
+	project1 := *NewEntry("p1", "P1", EntryTypeProject)
+	project2 := *NewEntry("p2", "P2", EntryTypeProject)
+	project3 := *NewEntry("p3", "P3", EntryTypeProject)
+	folder := *NewEntry("111", "Folder", EntryTypeFolder)
+
+	records := []JournalRecord{
+		{Event: JournalPlanned, Entry: project1},
+		{Event: JournalPlanned, Entry: project2},
+		{Event: JournalPlanned, Entry: project3},
+		{Event: JournalPlanned, Entry: folder},
+		{Event: JournalStarted, Entry: project1},
+		{Event: JournalSucceeded, Entry: project1},
+		{Event: JournalStarted, Entry: project2},
+		{Event: JournalFailed, Entry: project2, Error: "lien"},
+		{Event: JournalStarted, Entry: project3},
+		// A resumed run plans the remaining entries again
+		{Event: JournalPlanned, Entry: project2},
+	}
+
+	progress := ReplayJournal(records)
+
+	if len(progress.Planned) != 4 {
+		t.Fatalf("Expected 4 planned entries, got %d", len(progress.Planned))
+	}
+
+	if progress.Last[project3.ResourceName()] != JournalStarted {
+		t.Errorf("Expected p3 to be started, got %s", progress.Last[project3.ResourceName()])
+	}
+
+	remaining := progress.Remaining()
+	expected := []string{"p2", "p3", "111"}
+	if len(remaining) != len(expected) {
+		t.Fatalf("Expected %d remaining entries, got %d", len(expected), len(remaining))
+	}
+	for i, entry := range remaining {
+		if entry.Id != expected[i] {
+			t.Errorf("Expected remaining[%d] to be %s, got %s", i, expected[i], entry.Id)
+		}
+	}
+}
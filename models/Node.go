@@ -7,9 +7,13 @@ import (
 )
 
 type Node struct {
-	Current  *Entry
-	Values   []Entry
-	Children []*Node
+	Current  *Entry  `json:"entry" yaml:"entry"`
+	Values   []Entry `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Children []*Node `json:"children,omitempty" yaml:"children,omitempty"`
+	// Truncated marks a folder whose contents were not discovered because discovery stopped at the maximum depth
+	Truncated bool `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+	// Parent points to the enclosing node, nil for a root. It is rebuilt by Link after decoding.
+	Parent *Node `json:"-" yaml:"-"`
 }
 
 func NewNode(current *Entry, values []Entry) *Node {
@@ -20,12 +24,105 @@ func NewNode(current *Entry, values []Entry) *Node {
 	}
 }
 
+// AddChild attaches child below n and refreshes the ancestry of the whole child subtree
+func (n *Node) AddChild(child *Node) {
+	child.Parent = n
+	child.Current.Ancestry = n.childAncestry()
+	child.Link()
+	n.Children = append(n.Children, child)
+}
+
+// Depth returns the number of levels between n and the root of its tree
+func (n *Node) Depth() int {
+	depth := 0
+	for parent := n.Parent; parent != nil; parent = parent.Parent {
+		depth++
+	}
+
+	return depth
+}
+
+// ProjectCount returns the number of projects in the subtree rooted at n
+func (n *Node) ProjectCount() int {
+	count := len(n.Values)
+	for _, child := range n.Children {
+		count += child.ProjectCount()
+	}
+
+	return count
+}
+
+// FolderCount returns the number of folders below n, n itself excluded
+func (n *Node) FolderCount() int {
+	count := len(n.Children)
+	for _, child := range n.Children {
+		count += child.FolderCount()
+	}
+
+	return count
+}
+
+// Find returns the node of the folder or organization with the given id in the subtree rooted at n
+func (n *Node) Find(entryType EntryType, id string) *Node {
+	if n.Current.Type == entryType && n.Current.Id == id {
+		return n
+	}
+	for _, child := range n.Children {
+		if found := child.Find(entryType, id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Link sets the Parent pointers below n and propagates the ancestry of n.Current
+// to every project and folder in its subtree
+func (n *Node) Link() {
+	if n.Current == nil {
+		return
+	}
+
+	ancestry := n.childAncestry()
+	for i := range n.Values {
+		n.Values[i].Ancestry = ancestry
+	}
+	for _, child := range n.Children {
+		child.Parent = n
+		child.Current.Ancestry = ancestry
+		child.Link()
+	}
+}
+
 func (n *Node) Print(node treeprint.Tree) {
-	folder := node.AddBranch(fmt.Sprintf("%s (%s)", n.Current.Name, n.Current.Id))
+	n.PrintWithOptions(node, PrintOptions{})
+}
+
+func (n *Node) PrintWithOptions(node treeprint.Tree, options PrintOptions) {
+	label := options.label(*n.Current)
+	if n.Truncated {
+		label += " [truncated]"
+	}
+
+	// Branches below the display depth are collapsed into a summary
+	if options.Depth > 0 && n.Depth() >= options.Depth && (len(n.Values) > 0 || len(n.Children) > 0) {
+		node.AddNode(fmt.Sprintf("%s (+%d folders, %d projects)", label, n.FolderCount(), n.ProjectCount()))
+		return
+	}
+
+	folder := node.AddBranch(label)
 	for _, value := range n.Values {
-		folder.AddNode(fmt.Sprintf("%s (%s)", value.Name, value.Id))
+		folder.AddNode(options.label(value))
 	}
 	for _, child := range n.Children {
-		child.Print(folder)
+		child.PrintWithOptions(folder, options)
 	}
 }
+
+// childAncestry returns the ancestry shared by every direct descendant of n
+func (n *Node) childAncestry() []Ancestor {
+	ancestry := make([]Ancestor, 0, len(n.Current.Ancestry)+1)
+	ancestry = append(ancestry, n.Current.Ancestry...)
+
+	return append(ancestry, n.Current.AsAncestor())
+}
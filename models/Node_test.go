@@ -1,7 +1,10 @@
 package models
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/xlab/treeprint"
 )
 
 func TestNewNode(t *testing.T) {
@@ -101,3 +104,123 @@ func TestNode_AddChildren(t *testing.T) {
 		t.Errorf("Expected second child id to be 'child2', got %s", parent.Children[1].Current.Id)
 	}
 }
+
+func TestNode_AddChild(t *testing.T) {
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), nil)
+	child := NewNode(NewEntry("child", "Child", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	grandchild := NewNode(NewEntry("grandchild", "Grandchild", EntryTypeFolder), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+
+	child.AddChild(grandchild)
+	root.AddChild(child)
+
+	if child.Parent != root || grandchild.Parent != child {
+		t.Error("Expected AddChild to set parent pointers")
+	}
+
+	if root.Depth() != 0 || child.Depth() != 1 || grandchild.Depth() != 2 {
+		t.Errorf("Unexpected depths %d, %d, %d", root.Depth(), child.Depth(), grandchild.Depth())
+	}
+
+	if path := grandchild.Values[0].Path(); path != "Root/Child/Grandchild/Project 2" {
+		t.Errorf("Expected ancestry to be refreshed for the whole subtree, got %s", path)
+	}
+
+	if path := child.Values[0].Path(); path != "Root/Child/Project 1" {
+		t.Errorf("Expected path Root/Child/Project 1, got %s", path)
+	}
+}
+
+func TestNode_Link(t *testing.T) {
+	rootEntry := NewEntry("root", "Root", EntryTypeFolder)
+	rootEntry.Ancestry = []Ancestor{{Type: EntryTypeFolder, Id: "top", Name: "Top"}}
+	root := NewNode(rootEntry, []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	child := NewNode(NewEntry("child", "Child", EntryTypeFolder), nil)
+	root.Children = append(root.Children, child)
+
+	root.Link()
+
+	if child.Parent != root {
+		t.Error("Expected Link to set parent pointers")
+	}
+
+	if path := root.Values[0].Path(); path != "Top/Root/Project 1" {
+		t.Errorf("Expected path Top/Root/Project 1, got %s", path)
+	}
+
+	if path := child.Current.Path(); path != "Top/Root/Child" {
+		t.Errorf("Expected path Top/Root/Child, got %s", path)
+	}
+}
+
+func TestNode_Counts(t *testing.T) {
+	leaf := NewNode(NewEntry("leaf", "Leaf", EntryTypeFolder), []Entry{*NewEntry("p3", "P3", EntryTypeProject)})
+	middle := NewNode(NewEntry("middle", "Middle", EntryTypeFolder), []Entry{*NewEntry("p2", "P2", EntryTypeProject)})
+	middle.AddChild(leaf)
+	empty := NewNode(NewEntry("empty", "Empty", EntryTypeFolder), nil)
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), []Entry{*NewEntry("p1", "P1", EntryTypeProject)})
+	root.AddChild(middle)
+	root.AddChild(empty)
+
+	if root.ProjectCount() != 3 {
+		t.Errorf("Expected 3 projects, got %d", root.ProjectCount())
+	}
+
+	if root.FolderCount() != 3 {
+		t.Errorf("Expected 3 folders, got %d", root.FolderCount())
+	}
+
+	if leaf.ProjectCount() != 1 || leaf.FolderCount() != 0 {
+		t.Errorf("Unexpected leaf counts %d projects, %d folders", leaf.ProjectCount(), leaf.FolderCount())
+	}
+}
+
+func TestNode_PrintWithOptions_Depth(t *testing.T) {
+	deepest := NewNode(NewEntry("level3", "Level 3", EntryTypeFolder), []Entry{*NewEntry("p3", "Project 3", EntryTypeProject)})
+	deeper := NewNode(NewEntry("level2", "Level 2", EntryTypeFolder), []Entry{*NewEntry("p2", "Project 2", EntryTypeProject)})
+	deeper.AddChild(deepest)
+	level1 := NewNode(NewEntry("level1", "Level 1", EntryTypeFolder), nil)
+	level1.AddChild(deeper)
+	truncated := NewNode(NewEntry("cut", "Cut", EntryTypeFolder), nil)
+	truncated.Truncated = true
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), nil)
+	root.AddChild(level1)
+	root.AddChild(truncated)
+
+	output := treeprint.New()
+	root.PrintWithOptions(output, PrintOptions{Depth: 2})
+	rendered := output.String()
+
+	for _, expected := range []string{"Level 1 (level1)", "Level 2 (level2) (+1 folders, 2 projects)", "Cut (cut) [truncated]"} {
+		if !strings.Contains(rendered, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, rendered)
+		}
+	}
+
+	if strings.Contains(rendered, "Level 3") {
+		t.Errorf("Expected Level 3 to be collapsed, got:\n%s", rendered)
+	}
+}
+
+func TestNode_PrintWithOptions_StateAndLiens(t *testing.T) {
+	pending := NewEntry("p1", "Project 1", EntryTypeProject)
+	pending.LifecycleState = LifecycleStateDeleteRequested
+	active := NewEntry("p2", "Project 2", EntryTypeProject)
+	active.LifecycleState = LifecycleStateActive
+	locked := NewEntry("p3", "Project 3", EntryTypeProject)
+	locked.Liens = []Lien{{Name: "liens/p3-abc", Origin: "servicenetworking.googleapis.com"}, {Name: "liens/p3-def"}}
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), []Entry{*pending, *active, *locked})
+
+	output := treeprint.New()
+	root.PrintWithOptions(output, PrintOptions{})
+	rendered := output.String()
+
+	if !strings.Contains(rendered, "Project 1 (p1) [DELETE_REQUESTED]") {
+		t.Errorf("Expected pending project to show its state, got:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "Project 2 (p2)\n") {
+		t.Errorf("Expected active project without state, got:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "Project 3 (p3) [liens: servicenetworking.googleapis.com, liens/p3-def]") {
+		t.Errorf("Expected project to show its liens, got:\n%s", rendered)
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PlanVersion is the plan format written by this version of the tool
+const PlanVersion = 1
+
+// Plan is a reviewed, ordered list of deletions bound to the tree it was computed from
+type Plan struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Roots are the discovery roots, apply re-discovers them to detect drift
+	Roots []Entry `json:"roots"`
+	// Fingerprint is the Forest.Fingerprint of the tree the plan was computed from
+	Fingerprint string `json:"fingerprint"`
+	// Discovery holds the settings the roots were discovered with, nil in plans written before they were recorded
+	Discovery *PlanDiscovery `json:"discovery,omitempty"`
+	// Deletions are the entries to delete, in post order
+	Deletions []Entry `json:"deletions"`
+}
+
+// PlanDiscovery holds the settings that shape the discovered tree, apply discovers the roots with the same
+// settings so that a different flag cannot show up as drift
+type PlanDiscovery struct {
+	// MaxDepth is the --max-depth of the plan run, 0 when the whole tree was discovered
+	MaxDepth int `json:"maxDepth"`
+	// Backend is the --backend of the plan run
+	Backend string `json:"backend"`
+}
+
+func NewPlan(forest *Forest, deletions []Entry, discovery PlanDiscovery, createdAt time.Time) *Plan {
+	return &Plan{
+		Version:     PlanVersion,
+		CreatedAt:   createdAt.UTC(),
+		Roots:       forest.Roots(),
+		Fingerprint: forest.Fingerprint(),
+		Discovery:   &discovery,
+		Deletions:   deletions,
+	}
+}
+
+// Validate checks that the plan can be applied by this version of the tool
+func (p *Plan) Validate() error {
+	if p.Version != PlanVersion {
+		return fmt.Errorf("unsupported plan version %d, expected %d", p.Version, PlanVersion)
+	}
+	if len(p.Roots) == 0 {
+		return fmt.Errorf("plan has no roots")
+	}
+	if p.Fingerprint == "" {
+		return fmt.Errorf("plan has no fingerprint")
+	}
+
+	return nil
+}
+
+// CheckDrift returns an error when the forest no longer matches the tree the plan was computed from
+func (p *Plan) CheckDrift(forest *Forest) error {
+	if fingerprint := forest.Fingerprint(); fingerprint != p.Fingerprint {
+		return fmt.Errorf("resource tree has drifted since the plan was created: expected %s, got %s", p.Fingerprint, fingerprint)
+	}
+
+	return nil
+}
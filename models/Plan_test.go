@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPlan(t *testing.T) {
+	root := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	forest := NewForest(newTestTree(root))
+
+	plan := NewPlan(forest, forest.PostOrderTraversal(), PlanDiscovery{MaxDepth: 2, Backend: "api"}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)))
+
+	if plan.Version != PlanVersion {
+		t.Errorf("Expected version %d, got %d", PlanVersion, plan.Version)
+	}
+
+	if plan.CreatedAt.Location() != time.UTC {
+		t.Errorf("Expected creation time in UTC, got %v", plan.CreatedAt)
+	}
+
+	if len(plan.Roots) != 1 || plan.Roots[0].Id != "folder1" {
+		t.Errorf("Expected root folder1, got %+v", plan.Roots)
+	}
+
+	expected := []string{"proj1", "folder1"}
+	if len(plan.Deletions) != len(expected) {
+		t.Fatalf("Expected %d deletions, got %d", len(expected), len(plan.Deletions))
+	}
+	for i, entry := range plan.Deletions {
+		if entry.Id != expected[i] {
+			t.Errorf("Deletion %d: expected %s, got %s", i, expected[i], entry.Id)
+		}
+	}
+
+	if plan.Discovery == nil || plan.Discovery.MaxDepth != 2 || plan.Discovery.Backend != "api" {
+		t.Errorf("Expected the discovery settings to be recorded, got %+v", plan.Discovery)
+	}
+
+	if err := plan.Validate(); err != nil {
+		t.Errorf("Expected valid plan, got %v", err)
+	}
+
+	if err := plan.CheckDrift(forest); err != nil {
+		t.Errorf("Expected no drift, got %v", err)
+	}
+}
+
+func TestPlan_CheckDrift(t *testing.T) {
+	root := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil)
+	forest := NewForest(newTestTree(root))
+	plan := NewPlan(forest, forest.PostOrderTraversal(), PlanDiscovery{}, time.Now())
+
+	root.Values = append(root.Values, *NewEntry("proj1", "Project 1", EntryTypeProject))
+
+	if err := plan.CheckDrift(NewForest(newTestTree(root))); err == nil {
+		t.Error("Expected drift error, got nil")
+	}
+}
+
+func TestPlan_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		plan *Plan
+	}{
+		{name: "unsupported version", plan: &Plan{Version: PlanVersion + 1}},
+		{name: "no roots", plan: &Plan{Version: PlanVersion, Fingerprint: "sha256:abc"}},
+		{name: "no fingerprint", plan: &Plan{Version: PlanVersion, Roots: []Entry{*NewEntry("folder1", "Folder 1", EntryTypeFolder)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.plan.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+}
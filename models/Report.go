@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReportVersion is the run report format written by this version of the tool
+const ReportVersion = 1
+
+// ResultStatus is the outcome of a single deletion or restore
+type ResultStatus string
+
+const (
+	ResultDeleted  ResultStatus = "deleted"
+	ResultFailed   ResultStatus = "failed"
+	ResultDryRun   ResultStatus = "dry-run"
+	ResultRestored ResultStatus = "restored"
+	ResultSkipped  ResultStatus = "skipped"
+)
+
+// Result records the deletion or restore of a single folder or project
+type Result struct {
+	Entry  Entry        `json:"entry"`
+	Status ResultStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	// Cause is the class of the error, e.g. permission denied or lien present, when it is known
+	Cause string `json:"cause,omitempty"`
+	// Reason explains why an entry was skipped
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+	// RemovedLiens lists the liens removed right before the project was deleted
+	RemovedLiens []Lien `json:"removedLiens,omitempty"`
+}
+
+// RecoveryDeadline returns when a deleted entry is purged and can no longer be restored
+func (r Result) RecoveryDeadline() time.Time {
+	return r.Time.Add(PurgeWindow)
+}
+
+// Report is the record of a deletion or restore run, results are kept in the order the deletions finished
+type Report struct {
+	Version    int       `json:"version"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+	DryRun     bool      `json:"dryRun"`
+	// Retries is the number of calls retried after a transient error during the run
+	Retries int `json:"retries,omitempty"`
+	// RateLimitWaitSeconds is the total time calls spent waiting for the rate limit
+	RateLimitWaitSeconds float64  `json:"rateLimitWaitSeconds,omitempty"`
+	Results              []Result `json:"results"`
+
+	mutex sync.Mutex
+}
+
+func NewReport(startedAt time.Time, dryRun bool) *Report {
+	return &Report{
+		Version:   ReportVersion,
+		StartedAt: startedAt.UTC(),
+		DryRun:    dryRun,
+		Results:   make([]Result, 0),
+	}
+}
+
+// Add records a result, it is safe for concurrent use
+func (r *Report) Add(result Result) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Results = append(r.Results, result)
+}
+
+// Finish records when the run ended
+func (r *Report) Finish(finishedAt time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.FinishedAt = finishedAt.UTC()
+}
+
+// Count returns the number of results with the given status
+func (r *Report) Count(status ResultStatus) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// RestoreOrder returns the results of the entries that were actually deleted, in the reverse
+// order of their deletion. Folders therefore come top down and before the projects they held.
+func (r *Report) RestoreOrder() []Result {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result := make([]Result, 0, len(r.Results))
+	for i := len(r.Results) - 1; i >= 0; i-- {
+		if r.Results[i].Status == ResultDeleted {
+			result = append(result, r.Results[i])
+		}
+	}
+
+	return result
+}
+
+// Validate checks that the report can be read by this version of the tool
+func (r *Report) Validate() error {
+	if r.Version != ReportVersion {
+		return fmt.Errorf("unsupported report version %d, expected %d", r.Version, ReportVersion)
+	}
+
+	return nil
+}
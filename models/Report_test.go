@@ -0,0 +1,71 @@
+package models
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReport_Add_Concurrent(t *testing.T) {
+	report := NewReport(time.Now(), false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			status := ResultDeleted
+			if index%5 == 0 {
+				status = ResultFailed
+			}
+			report.Add(Result{Entry: *NewEntry("p", "P", EntryTypeProject), Status: status})
+		}(i)
+	}
+	wg.Wait()
+
+	if len(report.Results) != 50 {
+		t.Errorf("Expected 50 results, got %d", len(report.Results))
+	}
+
+	if report.Count(ResultFailed) != 10 || report.Count(ResultDeleted) != 40 {
+		t.Errorf("Expected 10 failed and 40 deleted, got %d and %d", report.Count(ResultFailed), report.Count(ResultDeleted))
+	}
+}
+
+func TestReport_Validate(t *testing.T) {
+	if err := NewReport(time.Now(), true).Validate(); err != nil {
+		t.Errorf("Expected valid report, got %v", err)
+	}
+
+	if err := (&Report{Version: ReportVersion + 1}).Validate(); err == nil {
+		t.Error("Expected validation error, got nil")
+	}
+}
+
+func TestReport_RestoreOrder(t *testing.T) {
+	report := NewReport(time.Now(), false)
+	report.Add(Result{Entry: *NewEntry("p1", "P1", EntryTypeProject), Status: ResultDeleted})
+	report.Add(Result{Entry: *NewEntry("p2", "P2", EntryTypeProject), Status: ResultFailed})
+	report.Add(Result{Entry: *NewEntry("child", "Child", EntryTypeFolder), Status: ResultDeleted})
+	report.Add(Result{Entry: *NewEntry("parent", "Parent", EntryTypeFolder), Status: ResultDeleted})
+
+	order := report.RestoreOrder()
+
+	expected := []string{"parent", "child", "p1"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(order))
+	}
+	for i, result := range order {
+		if result.Entry.Id != expected[i] {
+			t.Errorf("Expected result[%d] to be %s, got %s", i, expected[i], result.Entry.Id)
+		}
+	}
+}
+
+func TestResult_RecoveryDeadline(t *testing.T) {
+	result := Result{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	if deadline := result.RecoveryDeadline(); !deadline.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected deadline 2024-01-31, got %v", deadline)
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import "fmt"
+
+// Decision records whether a folder or project takes part in a deletion and why
+type Decision struct {
+	Excluded bool   `json:"excluded"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Selection holds the decision for every folder and project of a forest, keyed by resource name.
+// Entries without a decision are selected.
+type Selection map[string]Decision
+
+// Select evaluates decide on every folder and project of the forest. A folder that is selected
+// is excluded anyway when any of its descendants is excluded, since deleting it would fail, or
+// when it is truncated, since its contents are unknown.
+func (f *Forest) Select(decide func(Entry) Decision) Selection {
+	selection := make(Selection)
+	for _, tree := range f.Trees {
+		selection.selectNode(tree.Root, decide)
+	}
+
+	return selection
+}
+
+// selectNode records the decisions of the subtree rooted at node and returns the number of excluded entries in it
+func (s Selection) selectNode(node *Node, decide func(Entry) Decision) int {
+	blocked := 0
+	for _, value := range node.Values {
+		decision := decide(value)
+		s[value.ResourceName()] = decision
+		if decision.Excluded {
+			blocked++
+		}
+	}
+	for _, child := range node.Children {
+		blocked += s.selectNode(child, decide)
+	}
+
+	// An organization is never deleted, so it needs no decision
+	if node.Current.Type == EntryTypeOrganization {
+		return blocked
+	}
+
+	decision := decide(*node.Current)
+	if node.Truncated {
+		decision = Decision{Excluded: true, Reason: "truncated, contents not discovered"}
+	}
+	if !decision.Excluded && blocked > 0 {
+		decision = Decision{Excluded: true, Reason: fmt.Sprintf("contains %d excluded resources", blocked)}
+	}
+	s[node.Current.ResourceName()] = decision
+	if decision.Excluded {
+		blocked++
+	}
+
+	return blocked
+}
+
+// Excluded reports whether the entry is kept out of the deletion
+func (s Selection) Excluded(entry Entry) bool {
+	return s[entry.ResourceName()].Excluded
+}
+
+// Filter returns the entries that are not excluded, preserving their order
+func (s Selection) Filter(entries []Entry) []Entry {
+	result := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		if !s.Excluded(entry) {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
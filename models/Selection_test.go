@@ -0,0 +1,117 @@
+package models
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xlab/treeprint"
+)
+
+func newSelectionTestForest() *Forest {
+	shared := NewEntry("shared", "Shared", EntryTypeProject)
+	shared.Labels = map[string]string{"keep": "true"}
+
+	team := NewNode(NewEntry("team", "Team", EntryTypeFolder), []Entry{*NewEntry("p2", "Project 2", EntryTypeProject), *shared})
+	other := NewNode(NewEntry("other", "Other", EntryTypeFolder), []Entry{*NewEntry("p3", "Project 3", EntryTypeProject)})
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), []Entry{*NewEntry("p1", "Project 1", EntryTypeProject)})
+	root.AddChild(team)
+	root.AddChild(other)
+
+	return NewForest(newTestTree(root))
+}
+
+func keepLabelled(entry Entry) Decision {
+	if entry.Labels["keep"] == "true" {
+		return Decision{Excluded: true, Reason: "matches label keep=true"}
+	}
+
+	return Decision{}
+}
+
+func TestForest_Select_BlocksAncestors(t *testing.T) {
+	forest := newSelectionTestForest()
+	selection := forest.Select(keepLabelled)
+
+	traversed := selection.Filter(forest.PostOrderTraversal())
+
+	expected := []string{"p2", "p3", "other", "p1"}
+	if len(traversed) != len(expected) {
+		t.Fatalf("Expected %d entries, got %+v", len(expected), traversed)
+	}
+	for i, entry := range traversed {
+		if entry.Id != expected[i] {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected[i], entry.Id)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		entry  *Entry
+		reason string
+	}{
+		{name: "excluded project", entry: NewEntry("shared", "", EntryTypeProject), reason: "matches label keep=true"},
+		{name: "parent folder", entry: NewEntry("team", "", EntryTypeFolder), reason: "contains 1 excluded resources"},
+		{name: "root folder", entry: NewEntry("root", "", EntryTypeFolder), reason: "contains 2 excluded resources"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := selection[tt.entry.ResourceName()]
+			if !decision.Excluded || decision.Reason != tt.reason {
+				t.Errorf("Expected excluded with reason %q, got %+v", tt.reason, decision)
+			}
+		})
+	}
+}
+
+func TestForest_Select_OrganizationRoot(t *testing.T) {
+	organization := NewNode(NewEntry("100", "Acme", EntryTypeOrganization), nil)
+	organization.AddChild(NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil))
+
+	selection := NewForest(newTestTree(organization)).Select(keepLabelled)
+
+	if _, found := selection[organization.Current.ResourceName()]; found {
+		t.Error("Expected no decision for the organization")
+	}
+	if selection.Excluded(*organization.Children[0].Current) {
+		t.Error("Expected folder to be selected")
+	}
+}
+
+func TestNode_PrintWithOptions(t *testing.T) {
+	forest := newSelectionTestForest()
+	options := PrintOptions{Selection: forest.Select(keepLabelled)}
+
+	root := treeprint.New()
+	forest.Trees[0].Root.PrintWithOptions(root, options)
+	output := root.Bytes()
+
+	for _, expected := range []string{
+		"Shared (shared) [excluded: matches label keep=true]",
+		"Team (team) [excluded: contains 1 excluded resources]",
+		"Project 3 (p3)\n",
+	} {
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestForest_Select_TruncatedFolder(t *testing.T) {
+	truncated := NewNode(NewEntry("deep", "Deep", EntryTypeFolder), nil)
+	truncated.Truncated = true
+	root := NewNode(NewEntry("root", "Root", EntryTypeFolder), []Entry{*NewEntry("p1", "Project 1", EntryTypeProject)})
+	root.AddChild(truncated)
+
+	forest := NewForest(newTestTree(root))
+	selection := forest.Select(func(Entry) Decision { return Decision{} })
+
+	traversed := selection.Filter(forest.PostOrderTraversal())
+	if len(traversed) != 1 || traversed[0].Id != "p1" {
+		t.Errorf("Expected only p1 to be deleted, got %+v", traversed)
+	}
+
+	if decision := selection[truncated.Current.ResourceName()]; !decision.Excluded || decision.Reason != "truncated, contents not discovered" {
+		t.Errorf("Expected truncated folder to be excluded, got %+v", decision)
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SnapshotVersion is the snapshot format written by this version of the tool
+const SnapshotVersion = 1
+
+// Snapshot is the archived state of a discovered forest
+type Snapshot struct {
+	Version      int       `json:"version" yaml:"version"`
+	DiscoveredAt time.Time `json:"discoveredAt" yaml:"discoveredAt"`
+	Trees        []*Tree   `json:"trees" yaml:"trees"`
+}
+
+func NewSnapshot(forest *Forest, discoveredAt time.Time) *Snapshot {
+	return &Snapshot{
+		Version:      SnapshotVersion,
+		DiscoveredAt: discoveredAt.UTC(),
+		Trees:        forest.Trees,
+	}
+}
+
+// Validate checks that the snapshot can be read by this version of the tool
+func (s *Snapshot) Validate() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", s.Version, SnapshotVersion)
+	}
+	for i, tree := range s.Trees {
+		if tree == nil || tree.Root == nil || tree.Root.Current == nil {
+			return fmt.Errorf("snapshot tree %d has no root", i)
+		}
+	}
+
+	return nil
+}
+
+// Forest rebuilds the forest held by the snapshot, restoring the parent pointers lost in encoding
+func (s *Snapshot) Forest() *Forest {
+	forest := NewForest()
+	for _, tree := range s.Trees {
+		tree.Root.Link()
+		forest.Add(tree)
+	}
+
+	return forest
+}
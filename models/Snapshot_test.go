@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	tree := NewTree()
+	tree.Root = NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), nil)
+	discoveredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	snapshot := NewSnapshot(NewForest(tree), discoveredAt)
+
+	if snapshot.Version != SnapshotVersion {
+		t.Errorf("Expected version %d, got %d", SnapshotVersion, snapshot.Version)
+	}
+
+	if snapshot.DiscoveredAt.Location() != time.UTC || !snapshot.DiscoveredAt.Equal(discoveredAt) {
+		t.Errorf("Expected discovery time in UTC, got %v", snapshot.DiscoveredAt)
+	}
+
+	if err := snapshot.Validate(); err != nil {
+		t.Errorf("Expected valid snapshot, got %v", err)
+	}
+}
+
+func TestSnapshot_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot *Snapshot
+	}{
+		{name: "unsupported version", snapshot: &Snapshot{Version: SnapshotVersion + 1}},
+		{name: "tree without root", snapshot: &Snapshot{Version: SnapshotVersion, Trees: []*Tree{NewTree()}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.snapshot.Validate(); err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestSnapshot_Forest_RestoresParents(t *testing.T) {
+	child := &Node{Current: NewEntry("child", "Child", EntryTypeFolder)}
+	root := &Node{Current: NewEntry("root", "Root", EntryTypeFolder), Children: []*Node{child}}
+	snapshot := &Snapshot{Version: SnapshotVersion, Trees: []*Tree{{Root: root}}}
+
+	forest := snapshot.Forest()
+
+	if len(forest.Trees) != 1 {
+		t.Fatalf("Expected 1 tree, got %d", len(forest.Trees))
+	}
+
+	if child.Parent != root {
+		t.Error("Expected parent pointers to be restored")
+	}
+}
@@ -2,12 +2,13 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/xlab/treeprint"
 )
 
 type Tree struct {
-	Root *Node `json:"root"`
+	Root *Node `json:"root" yaml:"root"`
 }
 
 func NewTree() *Tree {
@@ -25,13 +26,74 @@ func (t *Tree) PostOrderTraversal(node *Node) []Entry {
 		result = append(result, t.PostOrderTraversal(node)...)
 	}
 	result = append(result, node.Values...)
-	result = append(result, *node.Current)
+	// An organization is only ever a discovery root, it must never end up in a deletion list
+	if node.Current.Type != EntryTypeOrganization {
+		result = append(result, *node.Current)
+	}
 
 	return result
 }
 
+// PrintOptions controls how trees are rendered
+type PrintOptions struct {
+	// Selection annotates entries with the reason they are excluded from or selected for deletion
+	Selection Selection
+	// Depth collapses every folder at this many levels below the root into a summary, 0 prints the whole tree
+	Depth int
+}
+
 func (t *Tree) Print() {
+	t.PrintWithOptions(PrintOptions{})
+}
+
+func (t *Tree) PrintWithOptions(options PrintOptions) {
+	if ancestry := t.ancestry(); ancestry != "" {
+		fmt.Println(ancestry)
+	}
 	root := treeprint.New()
-	t.Root.Print(root)
+	t.Root.PrintWithOptions(root, options)
 	fmt.Println(root.String())
 }
+
+// ancestry renders the known ancestors of the root, e.g. "Acme Org (1) / Engineering (2)"
+func (t *Tree) ancestry() string {
+	parts := make([]string, 0, len(t.Root.Current.Ancestry))
+	for _, ancestor := range t.Root.Current.Ancestry {
+		parts = append(parts, fmt.Sprintf("%s (%s)", ancestor.Name, ancestor.Id))
+	}
+
+	return strings.Join(parts, " / ")
+}
+
+// label renders an entry as "Name (Id)" followed by its lifecycle state unless active, its liens and its selection decision, if any
+func (o PrintOptions) label(entry Entry) string {
+	label := fmt.Sprintf("%s (%s)", entry.Name, entry.Id)
+	if entry.LifecycleState != "" && entry.LifecycleState != LifecycleStateActive {
+		label += " [" + entry.LifecycleState + "]"
+	}
+	if len(entry.Liens) > 0 {
+		origins := make([]string, 0, len(entry.Liens))
+		for _, lien := range entry.Liens {
+			origin := lien.Origin
+			if origin == "" {
+				origin = lien.Name
+			}
+			origins = append(origins, origin)
+		}
+		label += " [liens: " + strings.Join(origins, ", ") + "]"
+	}
+
+	decision, found := o.Selection[entry.ResourceName()]
+	switch {
+	case !found:
+		return label
+	case decision.Excluded && decision.Reason != "":
+		return label + " [excluded: " + decision.Reason + "]"
+	case decision.Excluded:
+		return label + " [excluded]"
+	case decision.Reason != "":
+		return label + " [selected: " + decision.Reason + "]"
+	default:
+		return label
+	}
+}
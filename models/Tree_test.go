@@ -156,3 +156,42 @@ func TestPostOrderTraversal_DeepNesting(t *testing.T) {
 		t.Errorf("Expected %+v, got %+v", expected, result)
 	}
 }
+
+func TestPostOrderTraversal_OrganizationRoot(t *testing.T) {
+	tree := NewTree()
+
+	folder := NewNode(NewEntry("folder1", "Folder 1", EntryTypeFolder), []Entry{*NewEntry("proj1", "Project 1", EntryTypeProject)})
+	organization := NewNode(NewEntry("org1", "Org 1", EntryTypeOrganization), []Entry{*NewEntry("proj2", "Project 2", EntryTypeProject)})
+	organization.Children = append(organization.Children, folder)
+
+	result := tree.PostOrderTraversal(organization)
+
+	expected := []Entry{
+		{Type: EntryTypeProject, Id: "proj1", Name: "Project 1"},
+		{Type: EntryTypeFolder, Id: "folder1", Name: "Folder 1"},
+		{Type: EntryTypeProject, Id: "proj2", Name: "Project 2"},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestTree_Ancestry(t *testing.T) {
+	rootEntry := NewEntry("111", "Sandboxes", EntryTypeFolder)
+	rootEntry.Ancestry = []Ancestor{
+		{Type: EntryTypeOrganization, Id: "100", Name: "Acme Org"},
+		{Type: EntryTypeFolder, Id: "110", Name: "Engineering"},
+	}
+	tree := NewTree()
+	tree.Root = NewNode(rootEntry, nil)
+
+	if ancestry := tree.ancestry(); ancestry != "Acme Org (100) / Engineering (110)" {
+		t.Errorf("Unexpected ancestry %q", ancestry)
+	}
+
+	tree.Root = NewNode(NewEntry("111", "111", EntryTypeFolder), nil)
+	if ancestry := tree.ancestry(); ancestry != "" {
+		t.Errorf("Expected empty ancestry for a root without ancestors, got %q", ancestry)
+	}
+}
@@ -0,0 +1,89 @@
+// Package backup archives project metadata before deletion, one JSON file per project and an index per run.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// IndexFile is the name of the index written in every run directory
+const IndexFile = "index.json"
+
+// runLayout names the run directory after the time the run started
+const runLayout = "20060102T150405Z"
+
+// Archive is the backup directory of a single run
+type Archive struct {
+	dir   string
+	index models.BackupIndex
+	mutex sync.Mutex
+}
+
+// NewArchive creates the directory of a run started at startedAt below root
+func NewArchive(root string, startedAt time.Time) (*Archive, error) {
+	dir := filepath.Join(root, startedAt.UTC().Format(runLayout))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	archive := &Archive{
+		dir: dir,
+		index: models.BackupIndex{
+			Version:   models.BackupVersion,
+			StartedAt: startedAt.UTC(),
+			Projects:  make([]models.BackupIndexEntry, 0),
+		},
+	}
+
+	return archive, archive.writeIndex()
+}
+
+// Dir returns the directory of the run
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// Add writes the backup of a project and records it in the index. The index is rewritten
+// after every project, so it stays accurate when a run is interrupted. It is safe for concurrent use.
+func (a *Archive) Add(backup *models.ProjectBackup) error {
+	file := backup.Project.Id + ".json"
+	if err := writeJSON(filepath.Join(a.dir, file), backup); err != nil {
+		return fmt.Errorf("failed to write backup of %s: %w", backup.Project.Id, err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.index.Projects = append(a.index.Projects, models.BackupIndexEntry{
+		ProjectId: backup.Project.Id,
+		Name:      backup.Project.Name,
+		Parent:    backup.Project.Parent,
+		File:      file,
+	})
+
+	return a.writeIndex()
+}
+
+func (a *Archive) writeIndex() error {
+	if err := writeJSON(filepath.Join(a.dir, IndexFile), a.index); err != nil {
+		return fmt.Errorf("failed to write backup index: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+func readJSON(t *testing.T, path string, value any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		t.Fatalf("Failed to decode %s: %v", path, err)
+	}
+}
+
+func TestArchive_Add(t *testing.T) {
+	startedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	archive, err := NewArchive(t.TempDir(), startedAt)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	if filepath.Base(archive.Dir()) != "20240102T030405Z" {
+		t.Errorf("Expected run directory named after the start time, got %s", archive.Dir())
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"sandbox-1", "sandbox-2", "sandbox-3"} {
+		wg.Add(1)
+		go func(projectId string) {
+			defer wg.Done()
+			project := models.NewEntry(projectId, projectId, models.EntryTypeProject)
+			project.Parent = "folders/111"
+			project.Labels = map[string]string{"env": "sandbox"}
+			backup := &models.ProjectBackup{
+				Version:         models.BackupVersion,
+				Project:         *project,
+				IAMPolicy:       json.RawMessage(`{"bindings":[]}`),
+				EnabledServices: []string{"compute.googleapis.com"},
+				Billing:         models.BillingInfo{AccountName: "billingAccounts/0000-1111", Enabled: true},
+			}
+			if err := archive.Add(backup); err != nil {
+				t.Errorf("Failed to add backup: %v", err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	var index models.BackupIndex
+	readJSON(t, filepath.Join(archive.Dir(), IndexFile), &index)
+
+	if len(index.Projects) != 3 {
+		t.Fatalf("Expected 3 projects in the index, got %d", len(index.Projects))
+	}
+
+	var backup models.ProjectBackup
+	readJSON(t, filepath.Join(archive.Dir(), index.Projects[0].File), &backup)
+
+	if backup.Project.Id != index.Projects[0].ProjectId || backup.Project.Parent != "folders/111" || backup.Project.Labels["env"] != "sandbox" {
+		t.Errorf("Expected project metadata in the backup, got %+v", backup.Project)
+	}
+	if !strings.Contains(string(backup.IAMPolicy), `"bindings"`) || backup.Billing.AccountName != "billingAccounts/0000-1111" {
+		t.Errorf("Expected IAM policy and billing in the backup, got %+v", backup)
+	}
+}
+
+func TestNewArchive_WritesEmptyIndex(t *testing.T) {
+	archive, err := NewArchive(t.TempDir(), time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	var index models.BackupIndex
+	readJSON(t, filepath.Join(archive.Dir(), IndexFile), &index)
+
+	if index.Version != models.BackupVersion || len(index.Projects) != 0 {
+		t.Errorf("Expected an empty index, got %+v", index)
+	}
+}
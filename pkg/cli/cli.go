@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
 	"github.com/spf13/cobra"
@@ -10,8 +12,8 @@ import (
 var cmd *cobra.Command
 
 // CommandHandlerFunc describes the header of functions that can be attached to a command
-// All the functions passed to AddCommand must respect it
-type CommandHandlerFunc func(ctx context.Context)
+// All the functions passed to AddCommand must respect it, the returned error becomes the error of Run
+type CommandHandlerFunc func(ctx context.Context) error
 
 // Init initializes the CLI service
 func Init(appID, shortDesc, longDesc string) {
@@ -32,8 +34,10 @@ func AddCommand(command, description string, handlerFunc CommandHandlerFunc) err
 		Use:   command,
 		Short: description,
 		Long:  description,
-		Run: func(cmd *cobra.Command, _ []string) {
-			handlerFunc(cmd.Context())
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// The flags were valid, so a failure of the handler is not answered with the usage
+			cmd.SilenceUsage = true
+			return handlerFunc(cmd.Context())
 		},
 	})
 
@@ -50,12 +54,38 @@ func AssignBoolFlag(target *bool, name string, defaultValue bool, description st
 	cmd.PersistentFlags().BoolVar(target, name, defaultValue, description)
 }
 
+// AssignStringSliceFlag set a repeatable string flag to CLI service
+func AssignStringSliceFlag(target *[]string, name string, defaultValue []string, description string) {
+	cmd.PersistentFlags().StringSliceVar(target, name, defaultValue, description)
+}
+
+// AssignStringArrayFlag set a repeatable string flag whose values are taken verbatim, commas included
+func AssignStringArrayFlag(target *[]string, name string, defaultValue []string, description string) {
+	cmd.PersistentFlags().StringArrayVar(target, name, defaultValue, description)
+}
+
 // AssignIntFlag set a bool flag to CLI service
 func AssignIntFlag(target *int, name string, defaultValue int, description string) {
 	cmd.PersistentFlags().IntVar(target, name, defaultValue, description)
 }
 
-// Run runs the CLI service with a context attached
+// AssignDurationFlag set a duration flag, e.g. 500ms or 30s, to CLI service
+func AssignDurationFlag(target *time.Duration, name string, defaultValue time.Duration, description string) {
+	cmd.PersistentFlags().DurationVar(target, name, defaultValue, description)
+}
+
+// AssignFloat64Flag set a float flag to CLI service
+func AssignFloat64Flag(target *float64, name string, defaultValue float64, description string) {
+	cmd.PersistentFlags().Float64Var(target, name, defaultValue, description)
+}
+
+// Run runs the CLI service with a context attached. It returns the error of the handler, or an error
+// wrapping ErrUsage when the command or its flags could not be parsed.
 func Run(ctx context.Context) error {
-	return cmd.ExecuteContext(ctx)
+	executed, err := cmd.ExecuteContextC(ctx)
+	if err != nil && !executed.SilenceUsage {
+		return fmt.Errorf("%w: %w", errors.ErrUsage, err)
+	}
+
+	return err
 }
@@ -2,9 +2,13 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 
-	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
 )
 
 func TestInit(t *testing.T) {
@@ -35,8 +39,9 @@ func TestAddCommand_Success(t *testing.T) {
 	Init("test-app", "short", "long")
 
 	called := false
-	testHandler := func(ctx context.Context) {
+	testHandler := func(ctx context.Context) error {
 		called = true
+		return nil
 	}
 
 	err := AddCommand("test-command", "Test command description", testHandler)
@@ -54,7 +59,7 @@ func TestAddCommand_Success(t *testing.T) {
 				t.Errorf("Expected description to be 'Test command description', got %s", subCmd.Short)
 			}
 			// Simulate running the command
-			subCmd.Run(subCmd, []string{})
+			_ = subCmd.RunE(subCmd, []string{})
 			break
 		}
 	}
@@ -71,10 +76,10 @@ func TestAddCommand_Success(t *testing.T) {
 func TestAddCommand_NotInitialized(t *testing.T) {
 	cmd = nil // Reset cmd to simulate uninitialized state
 
-	testHandler := func(ctx context.Context) {}
+	testHandler := func(ctx context.Context) error { return nil }
 	err := AddCommand("test-command", "Test command description", testHandler)
 
-	if err != errors.ErrNotInitialized {
+	if err != apperrors.ErrNotInitialized {
 		t.Errorf("Expected ErrNotInitialized, got %v", err)
 	}
 }
@@ -121,18 +126,67 @@ func TestAssignBoolFlag(t *testing.T) {
 	}
 }
 
+func TestAssignStringSliceFlag(t *testing.T) {
+	Init("test-app", "short", "long")
+
+	var testSlice []string
+	AssignStringSliceFlag(&testSlice, "test-slice", nil, "Test slice description")
+
+	// Get the flag to verify it was added
+	flag := cmd.PersistentFlags().Lookup("test-slice")
+	if flag == nil {
+		t.Fatal("String slice flag was not added")
+	}
+
+	if flag.Usage != "Test slice description" {
+		t.Errorf("Expected usage to be 'Test slice description', got %s", flag.Usage)
+	}
+
+	// Repeated and comma separated values are both accumulated
+	if err := cmd.PersistentFlags().Parse([]string{"--test-slice", "a", "--test-slice", "b,c"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if len(testSlice) != 3 || testSlice[0] != "a" || testSlice[1] != "b" || testSlice[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", testSlice)
+	}
+}
+
+func TestAssignStringArrayFlag(t *testing.T) {
+	Init("test-app", "short", "long")
+
+	var testArray []string
+	AssignStringArrayFlag(&testArray, "test-array", nil, "Test array description")
+
+	flag := cmd.PersistentFlags().Lookup("test-array")
+	if flag == nil {
+		t.Fatal("String array flag was not added")
+	}
+
+	// Commas are kept as part of the value
+	if err := cmd.PersistentFlags().Parse([]string{"--test-array", "a,b", "--test-array", "c"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	if len(testArray) != 2 || testArray[0] != "a,b" || testArray[1] != "c" {
+		t.Errorf("Expected [a,b c], got %v", testArray)
+	}
+}
+
 func TestMultipleCommands(t *testing.T) {
 	Init("test-app", "short", "long")
 
 	command1Called := false
 	command2Called := false
 
-	err1 := AddCommand("command1", "First command", func(ctx context.Context) {
+	err1 := AddCommand("command1", "First command", func(ctx context.Context) error {
 		command1Called = true
+		return nil
 	})
 
-	err2 := AddCommand("command2", "Second command", func(ctx context.Context) {
+	err2 := AddCommand("command2", "Second command", func(ctx context.Context) error {
 		command2Called = true
+		return nil
 	})
 
 	if err1 != nil {
@@ -151,7 +205,7 @@ func TestMultipleCommands(t *testing.T) {
 
 	// Test each command
 	for _, subCmd := range commands {
-		subCmd.Run(subCmd, []string{})
+		_ = subCmd.RunE(subCmd, []string{})
 	}
 
 	if !command1Called {
@@ -184,6 +238,42 @@ func TestAssignIntFlag(t *testing.T) {
 	}
 }
 
+func TestAssignDurationFlag(t *testing.T) {
+	Init("test-app", "short", "long")
+
+	var testDuration time.Duration
+	AssignDurationFlag(&testDuration, "test-duration", 1500*time.Millisecond, "Test duration description")
+
+	flag := cmd.PersistentFlags().Lookup("test-duration")
+	if flag == nil {
+		t.Fatal("Duration flag was not added")
+	}
+
+	if flag.DefValue != "1.5s" {
+		t.Errorf("Expected default value to be '1.5s', got %s", flag.DefValue)
+	}
+
+	if err := flag.Value.Set("250ms"); err != nil || testDuration != 250*time.Millisecond {
+		t.Errorf("Expected 250ms, got %v (%v)", testDuration, err)
+	}
+}
+
+func TestAssignFloat64Flag(t *testing.T) {
+	Init("test-app", "short", "long")
+
+	var testFloat float64
+	AssignFloat64Flag(&testFloat, "test-float", 0.5, "Test float description")
+
+	flag := cmd.PersistentFlags().Lookup("test-float")
+	if flag == nil {
+		t.Fatal("Float flag was not added")
+	}
+
+	if flag.DefValue != "0.5" {
+		t.Errorf("Expected default value to be '0.5', got %s", flag.DefValue)
+	}
+}
+
 func TestConcurrencyFlags(t *testing.T) {
 	Init("test-app", "short", "long")
 
@@ -289,12 +379,14 @@ func TestCommandsWithConcurrencyFlags(t *testing.T) {
 	command1Called := false
 	command2Called := false
 
-	err1 := AddCommand("command1", "First command", func(ctx context.Context) {
+	err1 := AddCommand("command1", "First command", func(ctx context.Context) error {
 		command1Called = true
+		return nil
 	})
 
-	err2 := AddCommand("command2", "Second command", func(ctx context.Context) {
+	err2 := AddCommand("command2", "Second command", func(ctx context.Context) error {
 		command2Called = true
+		return nil
 	})
 
 	if err1 != nil {
@@ -325,7 +417,7 @@ func TestCommandsWithConcurrencyFlags(t *testing.T) {
 
 	// Test command execution (flags should be available to commands)
 	for _, subCmd := range commands {
-		subCmd.Run(subCmd, []string{})
+		_ = subCmd.RunE(subCmd, []string{})
 	}
 
 	if !command1Called {
@@ -336,3 +428,51 @@ func TestCommandsWithConcurrencyFlags(t *testing.T) {
 		t.Error("Command2 was not called")
 	}
 }
+
+func TestRun_ReturnsHandlerError(t *testing.T) {
+	Init("test-app", "short", "long")
+	handlerErr := fmt.Errorf("wrapped: %w", apperrors.ErrPartialFailure)
+	_ = AddCommand("fail", "Failing command", func(ctx context.Context) error {
+		return handlerErr
+	})
+	cmd.SetArgs([]string{"fail"})
+
+	err := Run(context.Background())
+	if err != handlerErr {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+	if errors.Is(err, apperrors.ErrUsage) {
+		t.Error("Expected a handler error not to be a usage error")
+	}
+}
+
+func TestRun_UsageError(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"run", "--unknown"}},
+		{"unknown command", []string{"unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init("test-app", "short", "long")
+			called := false
+			_ = AddCommand("run", "Command", func(ctx context.Context) error {
+				called = true
+				return nil
+			})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			if err := Run(context.Background()); !errors.Is(err, apperrors.ErrUsage) {
+				t.Errorf("Expected a usage error, got %v", err)
+			}
+			if called {
+				t.Error("Expected the handler not to be called")
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+// classMarkers maps lower case fragments of gcloud and API error output to their class.
+// Classes are checked in order, a lien is also a failed precondition but the more specific reason wins.
+// Markers must be specific enough not to match inside unrelated words, e.g. "lien" is part of "client", and only
+// lien markers that mean a lien blocks the deletion count, an error on a liens/... resource keeps its own class.
+var classMarkers = []struct {
+	class   error
+	markers []string
+}{
+	{ErrLienPresent, []string{"a lien to prevent deletion", "has a lien"}},
+	{ErrUnauthenticated, []string{"unauthenticated", "reauthentication", "do not currently have an active account", "gcloud auth login", "invalid_grant", "invalid_client"}},
+	{ErrQuotaExceeded, []string{"resource_exhausted", "quota exceeded", "rate limit exceeded"}},
+	{ErrFailedPrecondition, []string{"failed_precondition", "failed precondition", "not empty"}},
+	{ErrPermissionDenied, []string{"permission_denied", "permission denied", "does not have permission", "not have permission"}},
+	{ErrNotFound, []string{"not_found", "not found", "does not exist"}},
+}
+
+// Classes lists the classes Classify can return, from the most to the least specific
+var Classes = []error{ErrLienPresent, ErrUnauthenticated, ErrQuotaExceeded, ErrFailedPrecondition, ErrPermissionDenied, ErrNotFound}
+
+// Classify maps the output of a failed gcloud command or API call to the class of the failure.
+// It returns nil when the output does not match a known class.
+func Classify(output string) error {
+	output = strings.ToLower(output)
+	for _, candidate := range classMarkers {
+		for _, marker := range candidate.markers {
+			if strings.Contains(output, marker) {
+				return candidate.class
+			}
+		}
+	}
+
+	return nil
+}
+
+// ClassOf returns the class err matches with errors.Is, or nil when it has none
+func ClassOf(err error) error {
+	for _, class := range Classes {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected error
+	}{
+		{
+			name:     "permission denied",
+			output:   "ERROR: (gcloud.projects.delete) [user@example.com] does not have permission to access projects instance [p1] (or it may not exist): The caller does not have permission",
+			expected: ErrPermissionDenied,
+		},
+		{
+			name:     "not found",
+			output:   "ERROR: (gcloud.resource-manager.folders.describe) NOT_FOUND: Requested entity was not found.",
+			expected: ErrNotFound,
+		},
+		{
+			name:     "folder not empty",
+			output:   "ERROR: (gcloud.resource-manager.folders.delete) FAILED_PRECONDITION: Folder is not empty.",
+			expected: ErrFailedPrecondition,
+		},
+		{
+			name:     "quota exceeded",
+			output:   "ERROR: (gcloud.projects.delete) RESOURCE_EXHAUSTED: Quota exceeded for quota metric 'Delete requests' of service 'cloudresourcemanager.googleapis.com'",
+			expected: ErrQuotaExceeded,
+		},
+		{
+			name:     "lien present",
+			output:   "ERROR: (gcloud.projects.delete) FAILED_PRECONDITION: A lien to prevent deletion was placed on the project by [servicenetworking.googleapis.com].",
+			expected: ErrLienPresent,
+		},
+		{
+			name:     "unauthenticated",
+			output:   "ERROR: (gcloud.projects.list) You do not currently have an active account selected. Please run:\n\n  $ gcloud auth login",
+			expected: ErrUnauthenticated,
+		},
+		{
+			name:     "lien name",
+			output:   "ERROR: (gcloud.projects.delete) FAILED_PRECONDITION: Project has a lien: liens/p1234-abcd",
+			expected: ErrLienPresent,
+		},
+		{
+			name:     "permission denied on a lien",
+			output:   "ERROR: (gcloud.alpha.resource-manager.liens.delete) PERMISSION_DENIED: Permission 'resourcemanager.projects.updateLiens' denied on resource 'liens/p1234-abcd'",
+			expected: ErrPermissionDenied,
+		},
+		{
+			name:     "lien not found",
+			output:   "ERROR: (gcloud.alpha.resource-manager.liens.delete) NOT_FOUND: Requested entity was not found: liens/p1234-abcd",
+			expected: ErrNotFound,
+		},
+		{
+			name:     "invalid client",
+			output:   "ERROR: (gcloud.projects.list) There was a problem refreshing your current auth tokens: ('invalid_client: Unauthorized', {'error': 'invalid_client', 'error_description': 'Unauthorized'})",
+			expected: ErrUnauthenticated,
+		},
+		{
+			name:     "client error",
+			output:   "ERROR: (gcloud.resource-manager.folders.delete) HTTPError 400: Client error: Folder is not empty",
+			expected: ErrFailedPrecondition,
+		},
+		{
+			name:     "unknown",
+			output:   "ERROR: (gcloud.projects.list) something unexpected happened",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Classify(tt.output); actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	err := fmt.Errorf("failed to delete p1: %w", errors.Join(errors.New("exit status 1"), ErrLienPresent))
+
+	if class := ClassOf(err); class != ErrLienPresent {
+		t.Errorf("Expected ErrLienPresent, got %v", class)
+	}
+
+	if class := ClassOf(errors.New("exit status 1")); class != nil {
+		t.Errorf("Expected no class, got %v", class)
+	}
+}
@@ -10,3 +10,39 @@ var ErrTargetNotPointer = errors.New("target is not pointer")
 
 // ErrFileDoesNotExist godoc
 var ErrFileDoesNotExist = errors.New("file does not exist")
+
+// ErrPathNotFound is returned when a segment of a display name path does not exist
+var ErrPathNotFound = errors.New("path not found")
+
+// ErrPathAmbiguous is returned when a segment of a display name path matches several resources
+var ErrPathAmbiguous = errors.New("path is ambiguous")
+
+// ErrInvalidSelector is returned when a filter expression cannot be parsed
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// ErrPermissionDenied is returned when the caller lacks the permission for a GCP call
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrNotFound is returned when a GCP resource does not exist or was already purged
+var ErrNotFound = errors.New("not found")
+
+// ErrFailedPrecondition is returned when a resource is not in a state that allows the call, e.g. a folder that is not empty
+var ErrFailedPrecondition = errors.New("failed precondition")
+
+// ErrQuotaExceeded is returned when a GCP quota or rate limit was hit
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ErrLienPresent is returned when a lien prevents the deletion of a project
+var ErrLienPresent = errors.New("lien present")
+
+// ErrUnauthenticated is returned when there are no valid credentials for GCP
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrUsage is returned when a command is called with missing, unknown or invalid flags
+var ErrUsage = errors.New("invalid usage")
+
+// ErrPartialFailure is returned when some of the deletions or restores of a run failed
+var ErrPartialFailure = errors.New("partial failure")
+
+// ErrTotalFailure is returned when none of the deletions or restores of a run succeeded
+var ErrTotalFailure = errors.New("total failure")
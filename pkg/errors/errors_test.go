@@ -26,6 +26,66 @@ func TestErrorMessages(t *testing.T) {
 			err:      ErrFileDoesNotExist,
 			expected: "file does not exist",
 		},
+		{
+			name:     "ErrPathNotFound",
+			err:      ErrPathNotFound,
+			expected: "path not found",
+		},
+		{
+			name:     "ErrPathAmbiguous",
+			err:      ErrPathAmbiguous,
+			expected: "path is ambiguous",
+		},
+		{
+			name:     "ErrInvalidSelector",
+			err:      ErrInvalidSelector,
+			expected: "invalid selector",
+		},
+		{
+			name:     "ErrPermissionDenied",
+			err:      ErrPermissionDenied,
+			expected: "permission denied",
+		},
+		{
+			name:     "ErrNotFound",
+			err:      ErrNotFound,
+			expected: "not found",
+		},
+		{
+			name:     "ErrFailedPrecondition",
+			err:      ErrFailedPrecondition,
+			expected: "failed precondition",
+		},
+		{
+			name:     "ErrQuotaExceeded",
+			err:      ErrQuotaExceeded,
+			expected: "quota exceeded",
+		},
+		{
+			name:     "ErrLienPresent",
+			err:      ErrLienPresent,
+			expected: "lien present",
+		},
+		{
+			name:     "ErrUnauthenticated",
+			err:      ErrUnauthenticated,
+			expected: "unauthenticated",
+		},
+		{
+			name:     "ErrUsage",
+			err:      ErrUsage,
+			expected: "invalid usage",
+		},
+		{
+			name:     "ErrPartialFailure",
+			err:      ErrPartialFailure,
+			expected: "partial failure",
+		},
+		{
+			name:     "ErrTotalFailure",
+			err:      ErrTotalFailure,
+			expected: "total failure",
+		},
 	}
 
 	for _, tt := range tests {
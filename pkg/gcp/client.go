@@ -0,0 +1,125 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+const (
+	// BackendGCloud shells out to the gcloud CLI for every call
+	BackendGCloud = "gcloud"
+	// BackendAPI talks to the Cloud Resource Manager v3 REST API directly
+	BackendAPI = "api"
+)
+
+// Backends lists the supported ResourceClient implementations
+var Backends = []string{BackendGCloud, BackendAPI}
+
+// ResourceClient defines the Resource Manager operations needed to discover and delete resources
+type ResourceClient interface {
+	GetOrganizations(ctx context.Context) ([]models.Entry, error)
+	GetProjects(ctx context.Context, parent models.Entry) ([]models.Entry, error)
+	GetFolders(ctx context.Context, parent models.Entry) ([]models.Entry, error)
+	DeleteProject(ctx context.Context, projectId string, dryRun bool) error
+	DeleteFolder(ctx context.Context, folderId string, dryRun bool) error
+	GetLifecycleState(ctx context.Context, entry models.Entry) (string, error)
+	UndeleteProject(ctx context.Context, projectId string, dryRun bool) error
+	UndeleteFolder(ctx context.Context, folderId string, dryRun bool) error
+	GetLiens(ctx context.Context, projectId string) ([]models.Lien, error)
+	GetTags(ctx context.Context, entry models.Entry) (map[string]string, error)
+	DeleteLien(ctx context.Context, name string, dryRun bool) error
+	GetIAMPolicy(ctx context.Context, projectId string) (json.RawMessage, error)
+	GetEnabledServices(ctx context.Context, projectId string) ([]string, error)
+	GetBillingInfo(ctx context.Context, projectId string) (models.BillingInfo, error)
+	CheckHealth(ctx context.Context) error
+}
+
+// GCloudClient is the ResourceClient implementation backed by the gcloud CLI
+type GCloudClient struct {
+	executor CommandExecutor
+}
+
+// NewGCloudClient creates a ResourceClient that runs gcloud through the given executor
+func NewGCloudClient(executor CommandExecutor) *GCloudClient {
+	return &GCloudClient{executor: executor}
+}
+
+// GetOrganizations lists the organizations visible to the active account
+func (c *GCloudClient) GetOrganizations(ctx context.Context) ([]models.Entry, error) {
+	return GetOrganizations(ctx, c.executor)
+}
+
+// GetProjects lists the projects directly under the given folder or organization
+func (c *GCloudClient) GetProjects(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	return GetProjects(ctx, parent.Id, c.executor)
+}
+
+// GetFolders lists the folders directly under the given folder or organization
+func (c *GCloudClient) GetFolders(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	if parent.Type == models.EntryTypeOrganization {
+		return GetOrganizationFolders(ctx, parent.Id, c.executor)
+	}
+	return GetFolders(ctx, parent.Id, c.executor)
+}
+
+// DeleteProject deletes the given project
+func (c *GCloudClient) DeleteProject(ctx context.Context, projectId string, dryRun bool) error {
+	return DeleteProject(ctx, projectId, dryRun, c.executor)
+}
+
+// DeleteFolder deletes the given folder
+func (c *GCloudClient) DeleteFolder(ctx context.Context, folderId string, dryRun bool) error {
+	return DeleteFolder(ctx, folderId, dryRun, c.executor)
+}
+
+// GetLifecycleState returns the current state of the given folder or project
+func (c *GCloudClient) GetLifecycleState(ctx context.Context, entry models.Entry) (string, error) {
+	return GetLifecycleState(ctx, entry, c.executor)
+}
+
+// UndeleteProject restores the given project
+func (c *GCloudClient) UndeleteProject(ctx context.Context, projectId string, dryRun bool) error {
+	return UndeleteProject(ctx, projectId, dryRun, c.executor)
+}
+
+// UndeleteFolder restores the given folder
+func (c *GCloudClient) UndeleteFolder(ctx context.Context, folderId string, dryRun bool) error {
+	return UndeleteFolder(ctx, folderId, dryRun, c.executor)
+}
+
+// GetLiens lists the liens placed on the given project
+func (c *GCloudClient) GetLiens(ctx context.Context, projectId string) ([]models.Lien, error) {
+	return GetLiens(ctx, projectId, c.executor)
+}
+
+// GetTags lists the effective tags of the given folder, project or organization
+func (c *GCloudClient) GetTags(ctx context.Context, entry models.Entry) (map[string]string, error) {
+	return GetTags(ctx, entry, c.executor)
+}
+
+// DeleteLien removes the given lien
+func (c *GCloudClient) DeleteLien(ctx context.Context, name string, dryRun bool) error {
+	return DeleteLien(ctx, name, dryRun, c.executor)
+}
+
+// GetIAMPolicy returns the IAM policy of the given project
+func (c *GCloudClient) GetIAMPolicy(ctx context.Context, projectId string) (json.RawMessage, error) {
+	return GetIAMPolicy(ctx, projectId, c.executor)
+}
+
+// GetEnabledServices lists the services enabled on the given project
+func (c *GCloudClient) GetEnabledServices(ctx context.Context, projectId string) ([]string, error) {
+	return GetEnabledServices(ctx, projectId, c.executor)
+}
+
+// GetBillingInfo returns the billing linkage of the given project
+func (c *GCloudClient) GetBillingInfo(ctx context.Context, projectId string) (models.BillingInfo, error) {
+	return GetBillingInfo(ctx, projectId, c.executor)
+}
+
+// CheckHealth verifies that gcloud is installed
+func (c *GCloudClient) CheckHealth(ctx context.Context) error {
+	return CheckHealth(ctx, c.executor)
+}
@@ -0,0 +1,84 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+func TestGCloudClient_DelegatesToExecutor(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(""),
+	}
+
+	var client ResourceClient = NewGCloudClient(mockExec)
+	ctx := context.Background()
+
+	if _, err := client.GetProjects(ctx, testFolder); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetFolders(ctx, testFolder); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.DeleteProject(ctx, "test-project", false); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.DeleteFolder(ctx, "test-folder", false); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if mockExec.GetCallCount() != 4 {
+		t.Errorf("Expected 4 command calls, got %d", mockExec.GetCallCount())
+	}
+
+	for i, call := range mockExec.CallLog {
+		if call.Name != "gcloud" {
+			t.Errorf("Call %d: expected command to be 'gcloud', got %s", i, call.Name)
+		}
+	}
+}
+
+func TestGCloudClient_DryRun(t *testing.T) {
+	mockExec := &MockExecutor{}
+	client := NewGCloudClient(mockExec)
+
+	if err := client.DeleteProject(context.Background(), "test-project", true); err != nil {
+		t.Errorf("Expected no error in dry run mode, got %v", err)
+	}
+
+	if mockExec.GetCallCount() != 0 {
+		t.Errorf("Expected 0 command calls in dry run mode, got %d", mockExec.GetCallCount())
+	}
+}
+
+func TestGCloudClient_OrganizationParent(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(""),
+	}
+	client := NewGCloudClient(mockExec)
+	organization := *models.NewEntry("999", "Acme", models.EntryTypeOrganization)
+
+	if _, err := client.GetFolders(context.Background(), organization); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	expectedArgs := []string{"resource-manager", "folders", "list", "--organization", "999", "--format", "json"}
+	lastCall := mockExec.GetLastCall()
+	if len(lastCall.Args) != len(expectedArgs) {
+		t.Fatalf("Expected args %v, got %v", expectedArgs, lastCall.Args)
+	}
+	for i, arg := range lastCall.Args {
+		if arg != expectedArgs[i] {
+			t.Errorf("Expected arg[%d] to be %s, got %s", i, expectedArgs[i], arg)
+		}
+	}
+
+	if _, err := client.GetProjects(context.Background(), organization); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if filter := mockExec.GetLastCall().Args[3]; filter != projectsFilter("999") {
+		t.Errorf("Expected filter %s, got %s", projectsFilter("999"), filter)
+	}
+}
@@ -0,0 +1,281 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// gcloudProject mirrors an element of `gcloud projects list --format=json`
+type gcloudProject struct {
+	ProjectId      string            `json:"projectId"`
+	ProjectNumber  flexibleString    `json:"projectNumber"`
+	Name           string            `json:"name"`
+	LifecycleState string            `json:"lifecycleState"`
+	CreateTime     time.Time         `json:"createTime"`
+	Labels         map[string]string `json:"labels"`
+	Parent         struct {
+		Type string `json:"type"`
+		Id   string `json:"id"`
+	} `json:"parent"`
+}
+
+// gcloudFolder mirrors an element of `gcloud resource-manager folders list --format=json`.
+// Depending on the gcloud version the state is reported as lifecycleState (v2) or state (v3).
+type gcloudFolder struct {
+	Name           string    `json:"name"`
+	DisplayName    string    `json:"displayName"`
+	Parent         string    `json:"parent"`
+	LifecycleState string    `json:"lifecycleState"`
+	State          string    `json:"state"`
+	CreateTime     time.Time `json:"createTime"`
+}
+
+// gcloudOrganization mirrors an element of `gcloud organizations list --format=json`
+type gcloudOrganization struct {
+	Name           string    `json:"name"`
+	DisplayName    string    `json:"displayName"`
+	LifecycleState string    `json:"lifecycleState"`
+	State          string    `json:"state"`
+	CreationTime   time.Time `json:"creationTime"`
+	CreateTime     time.Time `json:"createTime"`
+}
+
+// gcloudLien mirrors an element of `gcloud alpha resource-manager liens list --format=json`
+type gcloudLien struct {
+	Name         string   `json:"name"`
+	Reason       string   `json:"reason"`
+	Origin       string   `json:"origin"`
+	Restrictions []string `json:"restrictions"`
+}
+
+// effectiveTag mirrors an element of `gcloud resource-manager tags bindings list --effective --format=json`,
+// which has the same shape as the effectiveTags of the Resource Manager API
+type effectiveTag struct {
+	NamespacedTagKey   string `json:"namespacedTagKey"`
+	NamespacedTagValue string `json:"namespacedTagValue"`
+}
+
+// gcloudService mirrors an element of `gcloud services list --format=json`
+type gcloudService struct {
+	Config struct {
+		Name string `json:"name"`
+	} `json:"config"`
+}
+
+// flexibleString accepts both JSON strings and numbers, gcloud renders int64 fields as strings
+type flexibleString string
+
+func (f *flexibleString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*f = flexibleString(value)
+		return nil
+	}
+
+	var value json.Number
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*f = flexibleString(value.String())
+
+	return nil
+}
+
+// decodeProjects turns the JSON output of `gcloud projects list` into project entries
+func decodeProjects(out []byte) ([]models.Entry, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var projects []gcloudProject
+	if err := json.Unmarshal(out, &projects); err != nil {
+		return nil, fmt.Errorf("failed to decode projects: %w", err)
+	}
+
+	var result []models.Entry
+	for _, project := range projects {
+		if project.ProjectId == "" {
+			continue
+		}
+		entry := models.NewEntry(project.ProjectId, project.Name, models.EntryTypeProject)
+		entry.Number = string(project.ProjectNumber)
+		entry.LifecycleState = project.LifecycleState
+		entry.CreateTime = project.CreateTime
+		entry.Labels = project.Labels
+		if project.Parent.Id != "" {
+			entry.Parent = resourceName(project.Parent.Type, project.Parent.Id)
+		}
+		result = append(result, *entry)
+	}
+
+	return result, nil
+}
+
+// decodeFolders turns the JSON output of `gcloud resource-manager folders list` into folder entries
+func decodeFolders(out []byte) ([]models.Entry, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var folders []gcloudFolder
+	if err := json.Unmarshal(out, &folders); err != nil {
+		return nil, fmt.Errorf("failed to decode folders: %w", err)
+	}
+
+	var result []models.Entry
+	for _, folder := range folders {
+		id := strings.TrimPrefix(folder.Name, "folders/")
+		if id == "" {
+			continue
+		}
+		entry := models.NewEntry(id, folder.DisplayName, models.EntryTypeFolder)
+		entry.Parent = folder.Parent
+		entry.LifecycleState = folder.LifecycleState
+		if entry.LifecycleState == "" {
+			entry.LifecycleState = folder.State
+		}
+		entry.CreateTime = folder.CreateTime
+		result = append(result, *entry)
+	}
+
+	return result, nil
+}
+
+// decodeOrganizations turns the JSON output of `gcloud organizations list` into organization entries
+func decodeOrganizations(out []byte) ([]models.Entry, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var organizations []gcloudOrganization
+	if err := json.Unmarshal(out, &organizations); err != nil {
+		return nil, fmt.Errorf("failed to decode organizations: %w", err)
+	}
+
+	var result []models.Entry
+	for _, organization := range organizations {
+		id := strings.TrimPrefix(organization.Name, "organizations/")
+		if id == "" {
+			continue
+		}
+		entry := models.NewEntry(id, organization.DisplayName, models.EntryTypeOrganization)
+		entry.LifecycleState = organization.LifecycleState
+		if entry.LifecycleState == "" {
+			entry.LifecycleState = organization.State
+		}
+		entry.CreateTime = organization.CreationTime
+		if entry.CreateTime.IsZero() {
+			entry.CreateTime = organization.CreateTime
+		}
+		result = append(result, *entry)
+	}
+
+	return result, nil
+}
+
+// decodeLiens turns the JSON output of `gcloud alpha resource-manager liens list` into liens
+func decodeLiens(out []byte) ([]models.Lien, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var liens []gcloudLien
+	if err := json.Unmarshal(out, &liens); err != nil {
+		return nil, fmt.Errorf("failed to decode liens: %w", err)
+	}
+
+	var result []models.Lien
+	for _, lien := range liens {
+		if lien.Name == "" {
+			continue
+		}
+		result = append(result, models.Lien(lien))
+	}
+
+	return result, nil
+}
+
+// decodeTags turns the JSON output of `gcloud resource-manager tags bindings list --effective` into tags
+func decodeTags(out []byte) (map[string]string, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var tags []effectiveTag
+	if err := json.Unmarshal(out, &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+
+	return tagMap(tags), nil
+}
+
+// tagMap keys the short name of every tag value, e.g. prod, by its namespaced key, e.g. 123/env
+func tagMap(tags []effectiveTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.NamespacedTagKey == "" {
+			continue
+		}
+		result[tag.NamespacedTagKey] = strings.TrimPrefix(tag.NamespacedTagValue, tag.NamespacedTagKey+"/")
+	}
+
+	return result
+}
+
+// tagParent returns the full resource name tag bindings are listed for. Projects are addressed by number when known.
+func tagParent(entry models.Entry) string {
+	name := entry.ResourceName()
+	if entry.Type == models.EntryTypeProject && entry.Number != "" {
+		name = "projects/" + entry.Number
+	}
+
+	return "//cloudresourcemanager.googleapis.com/" + name
+}
+
+// decodeServices turns the JSON output of `gcloud services list` into service names
+func decodeServices(out []byte) ([]string, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var services []gcloudService
+	if err := json.Unmarshal(out, &services); err != nil {
+		return nil, fmt.Errorf("failed to decode services: %w", err)
+	}
+
+	var result []string
+	for _, service := range services {
+		if service.Config.Name != "" {
+			result = append(result, service.Config.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// resourceName converts a gcloud v1 parent reference (type "folder", id "123") into "folders/123"
+func resourceName(parentType, id string) string {
+	switch parentType {
+	case "folder":
+		return "folders/" + id
+	case "organization":
+		return "organizations/" + id
+	default:
+		return parentType + "/" + id
+	}
+}
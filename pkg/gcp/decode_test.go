@@ -0,0 +1,162 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDecode_Golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func([]byte) ([]models.Entry, error)
+	}{
+		{name: "projects", decode: decodeProjects},
+		{name: "folders", decode: decodeFolders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tt.name+".json"))
+			if err != nil {
+				t.Fatalf("Failed to read input: %v", err)
+			}
+
+			entries, err := tt.decode(input)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			actual, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				t.Fatalf("Failed to marshal entries: %v", err)
+			}
+			actual = append(actual, '\n')
+
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, actual, 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file: %v", err)
+			}
+
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("Decoded %s do not match %s, run with -update to refresh\ngot:\n%s", tt.name, golden, actual)
+			}
+		})
+	}
+}
+
+func TestDecodeProjects_NumericProjectNumber(t *testing.T) {
+	entries, err := decodeProjects([]byte(`[{"projectId":"p1","projectNumber":42}]`))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Number != "42" {
+		t.Errorf("Expected project number 42, got %+v", entries)
+	}
+}
+
+func TestDecodeProjects_EmptyList(t *testing.T) {
+	entries, err := decodeProjects([]byte("[]\n"))
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if entries != nil {
+		t.Errorf("Expected nil entries for empty list, got %v", entries)
+	}
+}
+
+func TestDecodeFolders_NotAList(t *testing.T) {
+	_, err := decodeFolders([]byte(`{"name":"folders/1"}`))
+
+	if err == nil {
+		t.Error("Expected error for non list output, got nil")
+	}
+}
+
+func FuzzDecodeProjects(f *testing.F) {
+	seed, err := os.ReadFile(filepath.Join("testdata", "projects.json"))
+	if err != nil {
+		f.Fatalf("Failed to read seed: %v", err)
+	}
+	f.Add(seed)
+	f.Add([]byte("[]"))
+	f.Add([]byte(`[{"projectId":"p","projectNumber":1,"parent":{"type":"","id":"x"}}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		entries, err := decodeProjects(data)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.Type != models.EntryTypeProject {
+				t.Errorf("Expected project entry, got %+v", entry)
+			}
+			if entry.Id == "" {
+				t.Errorf("Expected every project to have an id, got %+v", entry)
+			}
+		}
+	})
+}
+
+func FuzzDecodeFolders(f *testing.F) {
+	seed, err := os.ReadFile(filepath.Join("testdata", "folders.json"))
+	if err != nil {
+		f.Fatalf("Failed to read seed: %v", err)
+	}
+	f.Add(seed)
+	f.Add([]byte("[]"))
+	f.Add([]byte(`[{"name":"folders/"}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		entries, err := decodeFolders(data)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.Type != models.EntryTypeFolder {
+				t.Errorf("Expected folder entry, got %+v", entry)
+			}
+			if entry.Id == "" {
+				t.Errorf("Expected every folder to have an id, got %+v", entry)
+			}
+		}
+	})
+}
+
+func TestDecodeOrganizations(t *testing.T) {
+	entries, err := decodeOrganizations([]byte(`[{"name":"organizations/100","displayName":"acme.com","lifecycleState":"ACTIVE","creationTime":"2019-01-01T00:00:00Z"}]`))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 organization, got %d", len(entries))
+	}
+
+	if entries[0].Id != "100" || entries[0].Name != "acme.com" || entries[0].Type != models.EntryTypeOrganization {
+		t.Errorf("Unexpected organization %+v", entries[0])
+	}
+
+	if entries[0].CreateTime.IsZero() {
+		t.Error("Expected creation time to be set")
+	}
+}
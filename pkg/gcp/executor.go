@@ -1,8 +1,13 @@
 package gcp
 
 import (
+	"bytes"
 	"context"
 	"os/exec"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
 )
 
 // CommandExecutor defines the interface for executing external commands
@@ -10,13 +15,59 @@ type CommandExecutor interface {
 	ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
+// CommandError is returned when a command exits with an error, it keeps what the command printed to stderr
+type CommandError struct {
+	Err    error
+	Output []byte
+}
+
+func (e *CommandError) Error() string {
+	output := strings.TrimSpace(string(e.Output))
+	if output == "" {
+		return e.Err.Error()
+	}
+
+	return e.Err.Error() + ": " + output
+}
+
+// Unwrap exposes the underlying error along with the class of the failure read from the output,
+// so errors.Is(err, errors.ErrPermissionDenied) and the like work on command failures
+func (e *CommandError) Unwrap() []error {
+	if class := errors.Classify(string(e.Output)); class != nil {
+		return []error{e.Err, class}
+	}
+
+	return []error{e.Err}
+}
+
+// runCommand executes the command and returns what it printed to stdout. Stderr is kept apart, so warnings
+// such as update notices never end up in the JSON callers decode, and it is wrapped into a CommandError on failure.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, name, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		return stdout.Bytes(), &CommandError{Err: err, Output: stderr.Bytes()}
+	}
+	if stderr.Len() > 0 {
+		log := logger.New("gcp", "runCommand")
+		log.DebugWithExtra("Command printed to stderr", map[string]any{
+			"command": name,
+			"stderr":  strings.TrimSpace(stderr.String()),
+		})
+	}
+
+	return stdout.Bytes(), nil
+}
+
 // GCloudExecutor is the real implementation that executes gcloud commands
 type GCloudExecutor struct{}
 
 // ExecuteCommand executes the actual gcloud command
 func (g *GCloudExecutor) ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	return cmd.CombinedOutput()
+	return runCommand(ctx, name, args...)
 }
 
 type ConcurrentExecutor struct {
@@ -37,10 +88,7 @@ func (ce *ConcurrentExecutor) ExecuteCommand(ctx context.Context, name string, a
 		return nil, ctx.Err()
 	}
 
-	cmd := exec.CommandContext(ctx, name, args...)
-	output, err := cmd.CombinedOutput()
-
-	return output, err
+	return runCommand(ctx, name, args...)
 }
 
 // MockExecutor is a test implementation that returns predefined responses
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
 )
 
 func TestNewConcurrentExecutor(t *testing.T) {
@@ -270,3 +272,51 @@ func BenchmarkConcurrentExecutor_Parallel(b *testing.B) {
 		}
 	})
 }
+
+func TestGCloudExecutor_CommandError(t *testing.T) {
+	executor := &GCloudExecutor{}
+
+	_, err := executor.ExecuteCommand(context.Background(), "sh", "-c", "echo '[]'; echo 'UNAVAILABLE: try again' >&2; exit 1")
+
+	var commandErr *CommandError
+	if !errors.As(err, &commandErr) {
+		t.Fatalf("Expected CommandError, got %v", err)
+	}
+
+	if err.Error() != "exit status 1: UNAVAILABLE: try again" {
+		t.Errorf("Expected the output in the error message, got %q", err.Error())
+	}
+}
+
+func TestGCloudExecutor_SeparatesStderr(t *testing.T) {
+	executor := &GCloudExecutor{}
+
+	script := `echo 'WARNING: Python 3.8 is deprecated' >&2; echo '[{"projectId":"p1"}]'; echo 'Updates are available' >&2`
+	output, err := executor.ExecuteCommand(context.Background(), "sh", "-c", script)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	projects, err := decodeProjects(output)
+	if err != nil {
+		t.Fatalf("Expected stdout to decode despite the warnings, got %v", err)
+	}
+	if len(projects) != 1 || projects[0].Id != "p1" {
+		t.Errorf("Expected project p1, got %+v", projects)
+	}
+}
+
+func TestCommandError_Class(t *testing.T) {
+	err := &CommandError{
+		Err:    errors.New("exit status 1"),
+		Output: []byte("ERROR: (gcloud.projects.delete) FAILED_PRECONDITION: A lien to prevent deletion was placed on the project."),
+	}
+
+	if !errors.Is(err, apperrors.ErrLienPresent) {
+		t.Errorf("Expected ErrLienPresent, got %v", err)
+	}
+
+	if errors.Is(err, apperrors.ErrPermissionDenied) {
+		t.Error("Expected the lien error not to be permission denied")
+	}
+}
@@ -0,0 +1,73 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// fakeClient is an in-memory ResourceClient keyed by parent resource name
+type fakeClient struct {
+	organizations []models.Entry
+	folders       map[string][]models.Entry
+	projects      map[string][]models.Entry
+}
+
+func (f *fakeClient) GetOrganizations(_ context.Context) ([]models.Entry, error) {
+	return f.organizations, nil
+}
+
+func (f *fakeClient) GetProjects(_ context.Context, parent models.Entry) ([]models.Entry, error) {
+	return f.projects[parent.ResourceName()], nil
+}
+
+func (f *fakeClient) GetFolders(_ context.Context, parent models.Entry) ([]models.Entry, error) {
+	return f.folders[parent.ResourceName()], nil
+}
+
+func (f *fakeClient) DeleteProject(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (f *fakeClient) DeleteFolder(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (f *fakeClient) GetLifecycleState(_ context.Context, _ models.Entry) (string, error) {
+	return models.LifecycleStateActive, nil
+}
+
+func (f *fakeClient) UndeleteProject(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (f *fakeClient) UndeleteFolder(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (f *fakeClient) GetLiens(_ context.Context, _ string) ([]models.Lien, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetTags(_ context.Context, _ models.Entry) (map[string]string, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) DeleteLien(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
+func (f *fakeClient) GetIAMPolicy(_ context.Context, _ string) (json.RawMessage, error) {
+	return json.RawMessage(`{}`), nil
+}
+
+func (f *fakeClient) GetEnabledServices(_ context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) GetBillingInfo(_ context.Context, _ string) (models.BillingInfo, error) {
+	return models.BillingInfo{}, nil
+}
+
+func (f *fakeClient) CheckHealth(_ context.Context) error { return nil }
@@ -9,6 +9,16 @@ import (
 )
 
 func GetFolders(rootCtx context.Context, rootFolderId string, executor CommandExecutor) ([]models.Entry, error) {
+	return listFolders(rootCtx, "--folder", rootFolderId, executor)
+}
+
+// GetOrganizationFolders lists the top level folders of the given organization
+func GetOrganizationFolders(rootCtx context.Context, organizationId string, executor CommandExecutor) ([]models.Entry, error) {
+	return listFolders(rootCtx, "--organization", organizationId, executor)
+}
+
+// listFolders lists the folders whose parent is given by parentFlag (--folder or --organization)
+func listFolders(rootCtx context.Context, parentFlag, rootFolderId string, executor CommandExecutor) ([]models.Entry, error) {
 	ctx, cancelFunc := context.WithCancel(rootCtx)
 	defer cancelFunc()
 	log := logger.New("gcp", "GetFolders")
@@ -18,13 +28,13 @@ func GetFolders(rootCtx context.Context, rootFolderId string, executor CommandEx
 			"resource-manager",
 			"folders",
 			"list",
-			"--folder",
+			parentFlag,
 			rootFolderId,
 			"--format",
-			"csv[no-heading](ID,DISPLAY_NAME)",
+			"json",
 		},
 	})
-	out, err := executor.ExecuteCommand(ctx, "gcloud", "resource-manager", "folders", "list", "--folder", rootFolderId, "--format", "csv[no-heading](ID,DISPLAY_NAME)")
+	out, err := executor.ExecuteCommand(ctx, "gcloud", "resource-manager", "folders", "list", parentFlag, rootFolderId, "--format", "json")
 	if err != nil {
 		log.Error("Failed to run command", err)
 		return nil, err
@@ -36,15 +46,10 @@ func GetFolders(rootCtx context.Context, rootFolderId string, executor CommandEx
 		})
 		return nil, nil
 	}
-	result := make([]models.Entry, 0)
-	trimmed := strings.Trim(string(out), "\n")
-	for line := range strings.SplitSeq(trimmed, "\n") {
-		if line != "" {
-			vals := strings.Split(strings.Trim(line, "\n"), ",")
-			entry := models.NewEntry(vals[0], vals[1], models.EntryTypeFolder)
-
-			result = append(result, *entry)
-		}
+	result, err := decodeFolders(out)
+	if err != nil {
+		log.Error("Failed to decode command output", err)
+		return nil, err
 	}
 
 	log.DebugWithExtra("Gcloud command output", map[string]any{
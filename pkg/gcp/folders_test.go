@@ -20,7 +20,7 @@ func init() {
 
 func TestGetFolders_Success(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("folder1,Folder 1\nfolder2,Folder 2\nfolder3,Folder 3\n"),
+		MockOutput: []byte(`[{"name":"folders/folder1","displayName":"Folder 1"},{"name":"folders/folder2","displayName":"Folder 2"},{"name":"folders/folder3","displayName":"Folder 3"}]`),
 		MockError:  nil,
 	}
 
@@ -56,7 +56,7 @@ func TestGetFolders_Success(t *testing.T) {
 		t.Errorf("Expected command to be 'gcloud', got %s", lastCall.Name)
 	}
 
-	expectedArgs := []string{"resource-manager", "folders", "list", "--folder", "12345", "--format", "csv[no-heading](ID,DISPLAY_NAME)"}
+	expectedArgs := []string{"resource-manager", "folders", "list", "--folder", "12345", "--format", "json"}
 	if len(lastCall.Args) != len(expectedArgs) {
 		t.Errorf("Expected %d args, got %d", len(expectedArgs), len(lastCall.Args))
 	}
@@ -116,7 +116,7 @@ func TestGetFolders_CommandError(t *testing.T) {
 
 func TestGetFolders_WithNewlines(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("folder1,Folder 1\n\nfolder2,Folder 2\n\n\nfolder3,Folder 3\n"),
+		MockOutput: []byte("[\n  {\"name\": \"folders/folder1\", \"displayName\": \"Folder 1\"},\n\n  {\"name\": \"folders/folder2\", \"displayName\": \"Folder 2\"},\n\n\n  {\"name\": \"folders/folder3\", \"displayName\": \"Folder 3\"}\n]\n"),
 		MockError:  nil,
 	}
 
@@ -145,7 +145,7 @@ func TestGetFolders_WithNewlines(t *testing.T) {
 
 func TestGetFolders_SingleFolder(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("single-folder,Single Folder\n"),
+		MockOutput: []byte(`[{"name":"folders/single-folder","displayName":"Single Folder"}]`),
 		MockError:  nil,
 	}
 
@@ -168,7 +168,7 @@ func TestGetFolders_SingleFolder(t *testing.T) {
 
 func TestGetFolders_DifferentParentFolder(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("child-folder,Child Folder\n"),
+		MockOutput: []byte(`[{"name":"folders/child-folder","displayName":"Child Folder"}]`),
 		MockError:  nil,
 	}
 
@@ -196,6 +196,51 @@ func TestGetFolders_DifferentParentFolder(t *testing.T) {
 	}
 }
 
+func TestGetFolders_CommaInDisplayName(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"name":"folders/111","displayName":"Sandboxes, Legacy","parent":"folders/12345","lifecycleState":"ACTIVE","createTime":"2023-04-01T10:00:00.000Z"}]`),
+		MockError:  nil,
+	}
+
+	ctx := context.Background()
+	folders, err := GetFolders(ctx, "12345", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(folders) != 1 {
+		t.Fatalf("Expected 1 folder, got %d", len(folders))
+	}
+
+	folder := folders[0]
+	if folder.Id != "111" || folder.Name != "Sandboxes, Legacy" {
+		t.Errorf("Unexpected folder %+v", folder)
+	}
+
+	if folder.Parent != "folders/12345" || folder.LifecycleState != "ACTIVE" || folder.CreateTime.IsZero() {
+		t.Errorf("Expected metadata to be populated, got %+v", folder)
+	}
+}
+
+func TestGetFolders_InvalidJSON(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte("folder1,Folder 1\n"),
+		MockError:  nil,
+	}
+
+	ctx := context.Background()
+	folders, err := GetFolders(ctx, "12345", mockExec)
+
+	if err == nil {
+		t.Error("Expected error for non JSON output, got nil")
+	}
+
+	if folders != nil {
+		t.Errorf("Expected nil folders for non JSON output, got %v", folders)
+	}
+}
+
 func TestDeleteFolder_DryRun(t *testing.T) {
 	mockExec := &MockExecutor{}
 
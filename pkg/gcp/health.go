@@ -2,12 +2,15 @@ package gcp
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
 )
 
-func CheckHealth(rootCtx context.Context, executor CommandExecutor) {
+// CheckHealth verifies that gcloud can be run, returning an error when it fails or prints nothing
+func CheckHealth(rootCtx context.Context, executor CommandExecutor) error {
 	ctx, cancelFunc := context.WithCancel(rootCtx)
 	defer cancelFunc()
 
@@ -16,13 +19,17 @@ func CheckHealth(rootCtx context.Context, executor CommandExecutor) {
 
 	if err != nil {
 		log.Error("Failed to run command", err)
+		return fmt.Errorf("failed to run gcloud: %w", err)
 	}
 
 	if len(out) == 0 {
 		log.Error("Gcloud command returned no output")
+		return errors.New("gcloud version returned no output")
 	}
 
 	log.DebugWithExtra("Gcloud command output", map[string]any{
 		"output": strings.Split(strings.Trim(string(out), "\n"), "\n"),
 	})
+
+	return nil
 }
@@ -25,9 +25,9 @@ func TestCheckHealth_Success(t *testing.T) {
 
 	ctx := context.Background()
 
-	// CheckHealth doesn't return an error, it just logs
-	// This test ensures it doesn't panic and calls the right command
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 
 	// Verify the correct command was called
 	if mockExec.GetCallCount() != 1 {
@@ -59,9 +59,9 @@ func TestCheckHealth_CommandError(t *testing.T) {
 
 	ctx := context.Background()
 
-	// CheckHealth doesn't return an error, it just logs
-	// This test ensures it doesn't panic even when gcloud fails
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err == nil {
+		t.Error("Expected an error")
+	}
 
 	// Verify the command was attempted
 	if mockExec.GetCallCount() != 1 {
@@ -82,9 +82,9 @@ func TestCheckHealth_EmptyOutput(t *testing.T) {
 
 	ctx := context.Background()
 
-	// CheckHealth doesn't return an error, it just logs
-	// This test ensures it handles empty output gracefully
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err == nil {
+		t.Error("Expected an error")
+	}
 
 	// Verify the command was called
 	if mockExec.GetCallCount() != 1 {
@@ -108,7 +108,9 @@ please run:
 	ctx := context.Background()
 
 	// This test verifies that multiline output is handled correctly
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 
 	// Verify the command was called
 	if mockExec.GetCallCount() != 1 {
@@ -135,7 +137,9 @@ func TestCheckHealth_WithContext(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 
 	// Verify the command was called
 	if mockExec.GetCallCount() != 1 {
@@ -153,7 +157,7 @@ func TestCheckHealth_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	CheckHealth(ctx, mockExec)
+	_ = CheckHealth(ctx, mockExec)
 
 	// Even with a cancelled context, the function should not panic
 	// The command might still be called since cancellation is handled in the executor
@@ -171,7 +175,9 @@ func TestCheckHealth_GcloudNotInstalled(t *testing.T) {
 	ctx := context.Background()
 
 	// This simulates the case where gcloud is not installed
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err == nil {
+		t.Error("Expected an error")
+	}
 
 	// Verify the command was attempted
 	if mockExec.GetCallCount() != 1 {
@@ -192,7 +198,9 @@ func TestCheckHealth_OldGcloudVersion(t *testing.T) {
 
 	ctx := context.Background()
 
-	CheckHealth(ctx, mockExec)
+	if err := CheckHealth(ctx, mockExec); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
 
 	// Verify the command was called successfully
 	if mockExec.GetCallCount() != 1 {
@@ -214,9 +222,11 @@ func TestCheckHealth_CallSequence(t *testing.T) {
 	ctx := context.Background()
 
 	// Call CheckHealth multiple times
-	CheckHealth(ctx, mockExec)
-	CheckHealth(ctx, mockExec)
-	CheckHealth(ctx, mockExec)
+	for i := 0; i < 3; i++ {
+		if err := CheckHealth(ctx, mockExec); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
 
 	// Verify all calls were made
 	if mockExec.GetCallCount() != 3 {
@@ -0,0 +1,65 @@
+package gcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// GetLiens lists the liens placed on the given project
+func GetLiens(rootCtx context.Context, projectId string, executor CommandExecutor) ([]models.Lien, error) {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	args := []string{"alpha", "resource-manager", "liens", "list", "--project", projectId, "--format", "json"}
+
+	log := logger.New("gcp", "GetLiens")
+	log.DebugWithExtra("getLiens", map[string]any{
+		"cmd":  "gcloud",
+		"args": args,
+	})
+	out, err := executor.ExecuteCommand(ctx, "gcloud", args...)
+	if err != nil {
+		log.Error("Failed to run command", err)
+		return nil, err
+	}
+
+	result, err := decodeLiens(out)
+	if err != nil {
+		log.Error("Failed to decode command output", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Gcloud command output", map[string]any{
+		"projectId": projectId,
+		"output":    result,
+	})
+
+	return result, nil
+}
+
+// DeleteLien removes the lien with the given resource name, e.g. liens/p123-abc
+func DeleteLien(rootCtx context.Context, name string, dryRun bool, executor CommandExecutor) error {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	args := []string{"alpha", "resource-manager", "liens", "delete", strings.TrimPrefix(name, "liens/"), "--quiet"}
+
+	log := logger.New("gcp", "DeleteLien")
+	log.DebugWithExtra("DeleteLien", map[string]any{
+		"cmd":  "gcloud",
+		"args": args,
+	})
+	if dryRun {
+		return nil
+	}
+
+	if _, err := executor.ExecuteCommand(ctx, "gcloud", args...); err != nil {
+		log.Error("Failed to run command", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGetLiens_Success(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"name":"liens/p1-abc","reason":"Managed by Service Networking","origin":"servicenetworking.googleapis.com","restrictions":["resourcemanager.projects.delete"]}]`),
+	}
+
+	liens, err := GetLiens(context.Background(), "project1", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(liens) != 1 || liens[0].Name != "liens/p1-abc" || liens[0].Reason != "Managed by Service Networking" {
+		t.Fatalf("Unexpected liens %+v", liens)
+	}
+
+	expectedArgs := []string{"alpha", "resource-manager", "liens", "list", "--project", "project1", "--format", "json"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestGetLiens_Error(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockError: errors.New("alpha component not installed"),
+	}
+
+	if _, err := GetLiens(context.Background(), "project1", mockExec); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestDeleteLien(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if err := DeleteLien(context.Background(), "liens/p1-abc", false, mockExec); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedArgs := []string{"alpha", "resource-manager", "liens", "delete", "p1-abc", "--quiet"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestDeleteLien_DryRun(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if err := DeleteLien(context.Background(), "liens/p1-abc", true, mockExec); err != nil {
+		t.Errorf("Expected no error in dry run mode, got %v", err)
+	}
+
+	if mockExec.GetCallCount() != 0 {
+		t.Errorf("Expected 0 command calls in dry run mode, got %d", mockExec.GetCallCount())
+	}
+}
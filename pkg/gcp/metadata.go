@@ -0,0 +1,67 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// GetIAMPolicy returns the IAM policy of the given project as printed by gcloud
+func GetIAMPolicy(rootCtx context.Context, projectId string, executor CommandExecutor) (json.RawMessage, error) {
+	out, err := runMetadataCommand(rootCtx, "GetIAMPolicy", executor, "projects", "get-iam-policy", projectId, "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(out) {
+		return nil, fmt.Errorf("failed to decode IAM policy of %s", projectId)
+	}
+
+	return json.RawMessage(bytes.TrimSpace(out)), nil
+}
+
+// GetEnabledServices lists the names of the services enabled on the given project
+func GetEnabledServices(rootCtx context.Context, projectId string, executor CommandExecutor) ([]string, error) {
+	out, err := runMetadataCommand(rootCtx, "GetEnabledServices", executor, "services", "list", "--enabled", "--project", projectId, "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeServices(out)
+}
+
+// GetBillingInfo returns the billing account linked to the given project
+func GetBillingInfo(rootCtx context.Context, projectId string, executor CommandExecutor) (models.BillingInfo, error) {
+	out, err := runMetadataCommand(rootCtx, "GetBillingInfo", executor, "billing", "projects", "describe", projectId, "--format", "json")
+	if err != nil {
+		return models.BillingInfo{}, err
+	}
+
+	var billing models.BillingInfo
+	if err := json.Unmarshal(out, &billing); err != nil {
+		return models.BillingInfo{}, fmt.Errorf("failed to decode billing info: %w", err)
+	}
+
+	return billing, nil
+}
+
+func runMetadataCommand(rootCtx context.Context, action string, executor CommandExecutor, args ...string) ([]byte, error) {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New("gcp", action)
+	log.DebugWithExtra(action, map[string]any{
+		"cmd":  "gcloud",
+		"args": args,
+	})
+	out, err := executor.ExecuteCommand(ctx, "gcloud", args...)
+	if err != nil {
+		log.Error("Failed to run command", err)
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,73 @@
+package gcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestGetIAMPolicy(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`{"bindings":[{"role":"roles/owner","members":["user:a@example.com"]}],"etag":"BwX="}` + "\n"),
+	}
+
+	policy, err := GetIAMPolicy(context.Background(), "project1", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(policy) != `{"bindings":[{"role":"roles/owner","members":["user:a@example.com"]}],"etag":"BwX="}` {
+		t.Errorf("Unexpected policy %s", policy)
+	}
+
+	expectedArgs := []string{"projects", "get-iam-policy", "project1", "--format", "json"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestGetIAMPolicy_InvalidOutput(t *testing.T) {
+	mockExec := &MockExecutor{MockOutput: []byte("not json")}
+
+	if _, err := GetIAMPolicy(context.Background(), "project1", mockExec); err == nil {
+		t.Error("Expected error for invalid output, got nil")
+	}
+}
+
+func TestGetEnabledServices(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"config":{"name":"compute.googleapis.com"},"state":"ENABLED"},{"config":{"name":"storage.googleapis.com"},"state":"ENABLED"}]`),
+	}
+
+	services, err := GetEnabledServices(context.Background(), "project1", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !slices.Equal(services, []string{"compute.googleapis.com", "storage.googleapis.com"}) {
+		t.Errorf("Unexpected services %v", services)
+	}
+
+	expectedArgs := []string{"services", "list", "--enabled", "--project", "project1", "--format", "json"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestGetBillingInfo(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`{"billingAccountName":"billingAccounts/0000-1111","billingEnabled":true,"name":"projects/project1/billingInfo","projectId":"project1"}`),
+	}
+
+	billing, err := GetBillingInfo(context.Background(), "project1", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if billing.AccountName != "billingAccounts/0000-1111" || !billing.Enabled {
+		t.Errorf("Unexpected billing info %+v", billing)
+	}
+}
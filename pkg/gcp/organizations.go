@@ -0,0 +1,42 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// GetOrganizations lists the organizations visible to the active account
+func GetOrganizations(rootCtx context.Context, executor CommandExecutor) ([]models.Entry, error) {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New("gcp", "GetOrganizations")
+	log.DebugWithExtra("getOrganizations", map[string]any{
+		"cmd": "gcloud",
+		"args": []string{
+			"organizations",
+			"list",
+			"--format",
+			"json",
+		},
+	})
+	out, err := executor.ExecuteCommand(ctx, "gcloud", "organizations", "list", "--format", "json")
+	if err != nil {
+		log.Error("Failed to run command", err)
+		return nil, err
+	}
+
+	result, err := decodeOrganizations(out)
+	if err != nil {
+		log.Error("Failed to decode command output", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Gcloud command output", map[string]any{
+		"output": result,
+	})
+
+	return result, nil
+}
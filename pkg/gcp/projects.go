@@ -9,6 +9,12 @@ import (
 	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
 )
 
+// projectsFilter selects the projects directly below parentId. gcloud only lists ACTIVE projects unless the
+// filter names lifecycleState, so the ones pending deletion are asked for explicitly.
+func projectsFilter(parentId string) string {
+	return fmt.Sprintf("parent.id:%s AND lifecycleState:(%s OR %s)", parentId, models.LifecycleStateActive, models.LifecycleStateDeleteRequested)
+}
+
 func GetProjects(rootCtx context.Context, rootFolderId string, executor CommandExecutor) ([]models.Entry, error) {
 	ctx, cancelFunc := context.WithCancel(rootCtx)
 	defer cancelFunc()
@@ -20,12 +26,12 @@ func GetProjects(rootCtx context.Context, rootFolderId string, executor CommandE
 			"projects",
 			"list",
 			"--filter",
-			fmt.Sprintf("parent.id:%s", rootFolderId),
+			projectsFilter(rootFolderId),
 			"--format",
-			"csv[no-heading](projectId,name)",
+			"json",
 		},
 	})
-	out, err := executor.ExecuteCommand(ctx, "gcloud", "projects", "list", "--filter", fmt.Sprintf("parent.id:%s", rootFolderId), "--format", "csv[no-heading](projectId,name)")
+	out, err := executor.ExecuteCommand(ctx, "gcloud", "projects", "list", "--filter", projectsFilter(rootFolderId), "--format", "json")
 	if err != nil {
 		log.Error("Failed to run command", err)
 		return nil, err
@@ -37,14 +43,10 @@ func GetProjects(rootCtx context.Context, rootFolderId string, executor CommandE
 		})
 		return nil, nil
 	}
-	result := make([]models.Entry, 0)
-	trimmed := strings.Trim(string(out), "\n")
-	for line := range strings.SplitSeq(trimmed, "\n") {
-		if line != "" {
-			vals := strings.Split(strings.Trim(line, "\n"), ",")
-			entry := models.NewEntry(vals[1], vals[0], models.EntryTypeProject)
-			result = append(result, *entry)
-		}
+	result, err := decodeProjects(out)
+	if err != nil {
+		log.Error("Failed to decode command output", err)
+		return nil, err
 	}
 
 	log.DebugWithExtra("Gcloud command output", map[string]any{
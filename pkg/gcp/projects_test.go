@@ -3,6 +3,7 @@ package gcp
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/cupsadarius/gcp_resource_cleaner/models"
@@ -20,7 +21,7 @@ func init() {
 
 func TestGetProjects_Success(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("project1,Project 1\nproject2,Project 2\nproject3,Project 3\n"),
+		MockOutput: []byte(`[{"projectId":"project1","name":"Project 1"},{"projectId":"project2","name":"Project 2"},{"projectId":"project3","name":"Project 3"}]`),
 		MockError:  nil,
 	}
 
@@ -32,9 +33,9 @@ func TestGetProjects_Success(t *testing.T) {
 	}
 
 	expected := []models.Entry{
-		{Type: models.EntryTypeProject, Id: "Project 1", Name: "project1"},
-		{Type: models.EntryTypeProject, Id: "Project 2", Name: "project2"},
-		{Type: models.EntryTypeProject, Id: "Project 3", Name: "project3"},
+		{Type: models.EntryTypeProject, Id: "project1", Name: "Project 1"},
+		{Type: models.EntryTypeProject, Id: "project2", Name: "Project 2"},
+		{Type: models.EntryTypeProject, Id: "project3", Name: "Project 3"},
 	}
 	if len(projects) != len(expected) {
 		t.Errorf("Expected %d projects, got %d", len(expected), len(projects))
@@ -56,10 +57,80 @@ func TestGetProjects_Success(t *testing.T) {
 		t.Errorf("Expected command to be 'gcloud', got %s", lastCall.Name)
 	}
 
-	expectedArgs := []string{"projects", "list", "--filter", "parent.id:12345", "--format", "csv[no-heading](projectId,name)"}
+	expectedArgs := []string{"projects", "list", "--filter", "parent.id:12345 AND lifecycleState:(ACTIVE OR DELETE_REQUESTED)", "--format", "json"}
 	if len(lastCall.Args) != len(expectedArgs) {
 		t.Errorf("Expected %d args, got %d", len(expectedArgs), len(lastCall.Args))
 	}
+
+	for i, arg := range lastCall.Args {
+		if arg != expectedArgs[i] {
+			t.Errorf("Expected arg[%d] to be %s, got %s", i, expectedArgs[i], arg)
+		}
+	}
+}
+
+func TestGetProjects_PendingDeletion(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"projectId":"active-1","name":"Active","lifecycleState":"ACTIVE"},{"projectId":"deleted-1","name":"Deleted","lifecycleState":"DELETE_REQUESTED"}]`),
+	}
+
+	projects, err := GetProjects(context.Background(), "12345", mockExec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if filter := mockExec.GetLastCall().Args[3]; !strings.Contains(filter, "lifecycleState:(ACTIVE OR DELETE_REQUESTED)") {
+		t.Errorf("Expected the filter to ask for projects pending deletion, got %s", filter)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects))
+	}
+	if projects[0].PendingDeletion() || !projects[1].PendingDeletion() {
+		t.Errorf("Expected only deleted-1 to be pending deletion, got %+v", projects)
+	}
+}
+
+func TestGetProjects_Metadata(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"projectId":"sandbox-1","projectNumber":"123456789","name":"Sandbox, One","lifecycleState":"ACTIVE","createTime":"2023-04-01T10:00:00.000Z","labels":{"env":"sandbox"},"parent":{"type":"folder","id":"12345"}}]`),
+		MockError:  nil,
+	}
+
+	ctx := context.Background()
+	projects, err := GetProjects(ctx, "12345", mockExec)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(projects) != 1 {
+		t.Fatalf("Expected 1 project, got %d", len(projects))
+	}
+
+	project := projects[0]
+	if project.Id != "sandbox-1" || project.Name != "Sandbox, One" {
+		t.Errorf("Unexpected project %+v", project)
+	}
+
+	if project.Number != "123456789" {
+		t.Errorf("Expected project number 123456789, got %s", project.Number)
+	}
+
+	if project.Parent != "folders/12345" {
+		t.Errorf("Expected parent folders/12345, got %s", project.Parent)
+	}
+
+	if project.LifecycleState != "ACTIVE" {
+		t.Errorf("Expected lifecycle state ACTIVE, got %s", project.LifecycleState)
+	}
+
+	if project.CreateTime.IsZero() {
+		t.Error("Expected create time to be set")
+	}
+
+	if project.Labels["env"] != "sandbox" {
+		t.Errorf("Expected label env=sandbox, got %v", project.Labels)
+	}
 }
 
 func TestGetProjects_EmptyResult(t *testing.T) {
@@ -100,7 +171,7 @@ func TestGetProjects_CommandError(t *testing.T) {
 
 func TestGetProjects_WithNewlines(t *testing.T) {
 	mockExec := &MockExecutor{
-		MockOutput: []byte("project1,Project 1\n\nproject2,Project 2\n\n\nproject3,Project 3\n"),
+		MockOutput: []byte("[\n  {\"projectId\": \"project1\", \"name\": \"Project 1\"},\n\n  {\"projectId\": \"project2\", \"name\": \"Project 2\"},\n\n\n  {\"projectId\": \"project3\", \"name\": \"Project 3\"}\n]\n"),
 		MockError:  nil,
 	}
 
@@ -112,9 +183,9 @@ func TestGetProjects_WithNewlines(t *testing.T) {
 	}
 
 	expected := []models.Entry{
-		{Type: models.EntryTypeProject, Id: "Project 1", Name: "project1"},
-		{Type: models.EntryTypeProject, Id: "Project 2", Name: "project2"},
-		{Type: models.EntryTypeProject, Id: "Project 3", Name: "project3"},
+		{Type: models.EntryTypeProject, Id: "project1", Name: "Project 1"},
+		{Type: models.EntryTypeProject, Id: "project2", Name: "Project 2"},
+		{Type: models.EntryTypeProject, Id: "project3", Name: "Project 3"},
 	}
 	if len(projects) != len(expected) {
 		t.Errorf("Expected %d projects, got %d", len(expected), len(projects))
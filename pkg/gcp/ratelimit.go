@@ -0,0 +1,151 @@
+package gcp
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// TokenBucket allows a steady number of calls per second with bursts of up to burst calls
+type TokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	mutex  sync.Mutex
+}
+
+// NewTokenBucket creates a full bucket refilled at qps tokens per second, holding up to one second of calls
+func NewTokenBucket(qps float64) *TokenBucket {
+	burst := math.Max(1, math.Floor(qps))
+
+	return &TokenBucket{rate: qps, burst: burst, tokens: burst, last: time.Now(), now: time.Now}
+}
+
+// reserve takes a token and returns how long the caller has to wait before using it.
+// Tokens may go negative, which queues callers behind each other in arrival order.
+func (b *TokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available and returns how long it waited
+func (b *TokenBucket) Wait(ctx context.Context) (time.Duration, error) {
+	delay := b.reserve()
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return delay, ctx.Err()
+	case <-time.After(delay):
+		return delay, nil
+	}
+}
+
+// RateLimiter keeps separate per second budgets for read and write calls, a nil bucket is unlimited
+type RateLimiter struct {
+	read   *TokenBucket
+	write  *TokenBucket
+	waited atomic.Int64
+}
+
+// NewRateLimiter creates a limiter with the given calls per second, 0 leaves that kind of call unlimited
+func NewRateLimiter(readQPS, writeQPS float64) *RateLimiter {
+	limiter := &RateLimiter{}
+	if readQPS > 0 {
+		limiter.read = NewTokenBucket(readQPS)
+	}
+	if writeQPS > 0 {
+		limiter.write = NewTokenBucket(writeQPS)
+	}
+
+	return limiter
+}
+
+// Wait blocks until the budget of the given kind of call allows another one
+func (l *RateLimiter) Wait(ctx context.Context, write bool, call string) error {
+	bucket := l.read
+	if write {
+		bucket = l.write
+	}
+	if bucket == nil {
+		return nil
+	}
+
+	waited, err := bucket.Wait(ctx)
+	if waited > 0 {
+		l.waited.Add(int64(waited))
+		logger.New("gcp", "RateLimiter").DebugWithExtra("Waited for rate limit", map[string]any{
+			"call":   call,
+			"write":  write,
+			"waited": waited.String(),
+		})
+	}
+
+	return err
+}
+
+// Waited returns the total time calls spent waiting for the rate limit
+func (l *RateLimiter) Waited() time.Duration {
+	return time.Duration(l.waited.Load())
+}
+
+// RateLimitedExecutor waits for the rate limit before running each command.
+// Delete and undelete commands use the write budget, everything else the read budget.
+type RateLimitedExecutor struct {
+	executor CommandExecutor
+	limiter  *RateLimiter
+}
+
+func NewRateLimitedExecutor(executor CommandExecutor, limiter *RateLimiter) *RateLimitedExecutor {
+	return &RateLimitedExecutor{executor: executor, limiter: limiter}
+}
+
+func (e *RateLimitedExecutor) ExecuteCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if err := e.limiter.Wait(ctx, isWriteCommand(args), name); err != nil {
+		return nil, err
+	}
+
+	return e.executor.ExecuteCommand(ctx, name, args...)
+}
+
+// isWriteCommand reports whether the gcloud arguments change a resource
+func isWriteCommand(args []string) bool {
+	return slices.Contains(args, "delete") || slices.Contains(args, "undelete")
+}
+
+// rateLimitedTransport waits for the rate limit before sending each request
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Reading an IAM policy is a POST as well, only deletions and undeletions use the write budget
+	write := req.Method == http.MethodDelete || strings.HasSuffix(req.URL.Path, ":undelete")
+	if err := t.limiter.Wait(req.Context(), write, req.Method+" "+req.URL.Path); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
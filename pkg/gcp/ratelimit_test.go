@@ -0,0 +1,95 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := NewTokenBucket(2)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	// A full bucket allows a burst of one second of calls
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Errorf("Expected call %d of the burst not to wait, got %s", i+1, delay)
+		}
+	}
+
+	if delay := bucket.reserve(); delay != 500*time.Millisecond {
+		t.Errorf("Expected the third call to wait 500ms, got %s", delay)
+	}
+
+	if delay := bucket.reserve(); delay != time.Second {
+		t.Errorf("Expected the fourth call to queue behind the third and wait 1s, got %s", delay)
+	}
+
+	now = now.Add(3 * time.Second)
+	if delay := bucket.reserve(); delay != 0 {
+		t.Errorf("Expected no wait once the bucket refilled, got %s", delay)
+	}
+}
+
+func TestIsWriteCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"projects", "list", "--format", "json"}, false},
+		{[]string{"projects", "delete", "p1", "--quiet"}, true},
+		{[]string{"resource-manager", "folders", "undelete", "111", "--quiet"}, true},
+		{[]string{"alpha", "resource-manager", "liens", "delete", "p1-abc", "--quiet"}, true},
+	}
+
+	for _, tt := range tests {
+		if actual := isWriteCommand(tt.args); actual != tt.expected {
+			t.Errorf("Expected isWriteCommand(%v) to be %v, got %v", tt.args, tt.expected, actual)
+		}
+	}
+}
+
+func TestRateLimitedExecutor_SeparateBudgets(t *testing.T) {
+	mockExec := &MockExecutor{}
+	limiter := NewRateLimiter(0, 50)
+	executor := NewRateLimitedExecutor(mockExec, limiter)
+	ctx := context.Background()
+
+	// Reads are unlimited
+	for i := 0; i < 10; i++ {
+		_, _ = executor.ExecuteCommand(ctx, "gcloud", "projects", "list")
+	}
+	if limiter.Waited() != 0 {
+		t.Errorf("Expected unlimited reads not to wait, waited %s", limiter.Waited())
+	}
+
+	// 51 deletes exceed the burst of 50
+	for i := 0; i < 51; i++ {
+		_, _ = executor.ExecuteCommand(ctx, "gcloud", "projects", "delete", "p1", "--quiet")
+	}
+	if limiter.Waited() <= 0 {
+		t.Error("Expected deletes beyond the burst to wait")
+	}
+
+	if mockExec.GetCallCount() != 61 {
+		t.Errorf("Expected 61 calls, got %d", mockExec.GetCallCount())
+	}
+}
+
+func TestRateLimitedExecutor_Cancelled(t *testing.T) {
+	mockExec := &MockExecutor{}
+	executor := NewRateLimitedExecutor(mockExec, NewRateLimiter(0.001, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, _ = executor.ExecuteCommand(ctx, "gcloud", "projects", "list")
+	cancel()
+	if _, err := executor.ExecuteCommand(ctx, "gcloud", "projects", "list"); err == nil {
+		t.Error("Expected an error when the context is cancelled while waiting, got nil")
+	}
+
+	if mockExec.GetCallCount() != 1 {
+		t.Errorf("Expected 1 call, got %d", mockExec.GetCallCount())
+	}
+}
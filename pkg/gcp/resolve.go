@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// ResolveFolderPath resolves a display name path such as "Acme Org/Engineering/Sandboxes"
+// to the resource it designates. The first segment is the organization display name,
+// every following segment is the display name of a folder below the previous one.
+// The returned entry carries the resolved ancestry.
+func ResolveFolderPath(ctx context.Context, client ResourceClient, path string) (models.Entry, error) {
+	log := logger.New("gcp", "ResolveFolderPath")
+
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return models.Entry{}, fmt.Errorf("%w: empty folder path", errors.ErrPathNotFound)
+	}
+
+	organizations, err := client.GetOrganizations(ctx)
+	if err != nil {
+		return models.Entry{}, err
+	}
+	current, err := pickByName(organizations, segments[0], "organization", "")
+	if err != nil {
+		return models.Entry{}, err
+	}
+
+	for _, segment := range segments[1:] {
+		folders, err := client.GetFolders(ctx, current)
+		if err != nil {
+			return models.Entry{}, err
+		}
+		next, err := pickByName(folders, segment, "folder", current.Path())
+		if err != nil {
+			return models.Entry{}, err
+		}
+		next.Ancestry = append(append([]models.Ancestor{}, current.Ancestry...), current.AsAncestor())
+		current = next
+	}
+
+	log.DebugWithExtra("Resolved folder path", map[string]any{
+		"path":     path,
+		"resource": current.ResourceName(),
+	})
+
+	return current, nil
+}
+
+// splitPath splits a display name path on slashes, ignoring empty segments
+func splitPath(path string) []string {
+	var segments []string
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// pickByName returns the only entry named name, failing when there is none or more than one
+func pickByName(entries []models.Entry, name, kind, parentPath string) (models.Entry, error) {
+	var matches []models.Entry
+	for _, entry := range entries {
+		if entry.Name == name {
+			matches = append(matches, entry)
+		}
+	}
+
+	location := ""
+	if parentPath != "" {
+		location = fmt.Sprintf(" under %q", parentPath)
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.Entry{}, fmt.Errorf("%w: no %s named %q%s", errors.ErrPathNotFound, kind, name, location)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, match := range matches {
+			ids = append(ids, match.Id)
+		}
+		return models.Entry{}, fmt.Errorf("%w: %d %ss named %q%s (%s)", errors.ErrPathAmbiguous, len(matches), kind, name, location, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,85 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func newResolveClient() *fakeClient {
+	return &fakeClient{
+		organizations: []models.Entry{
+			*models.NewEntry("100", "Acme Org", models.EntryTypeOrganization),
+			*models.NewEntry("200", "Other Org", models.EntryTypeOrganization),
+		},
+		folders: map[string][]models.Entry{
+			"organizations/100": {
+				*models.NewEntry("110", "Engineering", models.EntryTypeFolder),
+				*models.NewEntry("120", "Finance", models.EntryTypeFolder),
+			},
+			"folders/110": {
+				*models.NewEntry("111", "Sandboxes", models.EntryTypeFolder),
+				*models.NewEntry("112", "Shared", models.EntryTypeFolder),
+				*models.NewEntry("113", "Shared", models.EntryTypeFolder),
+			},
+		},
+	}
+}
+
+func TestResolveFolderPath_Success(t *testing.T) {
+	entry, err := ResolveFolderPath(context.Background(), newResolveClient(), "Acme Org/Engineering/Sandboxes")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if entry.Id != "111" || entry.Type != models.EntryTypeFolder {
+		t.Errorf("Expected folder 111, got %+v", entry)
+	}
+
+	if entry.Path() != "Acme Org/Engineering/Sandboxes" {
+		t.Errorf("Expected resolved ancestry, got %s", entry.Path())
+	}
+
+	if entry.Ancestry[0].Type != models.EntryTypeOrganization || entry.Ancestry[0].Id != "100" {
+		t.Errorf("Expected the organization to head the ancestry, got %+v", entry.Ancestry)
+	}
+}
+
+func TestResolveFolderPath_Organization(t *testing.T) {
+	entry, err := ResolveFolderPath(context.Background(), newResolveClient(), "/Acme Org/")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if entry.Id != "100" || entry.Type != models.EntryTypeOrganization {
+		t.Errorf("Expected organization 100, got %+v", entry)
+	}
+}
+
+func TestResolveFolderPath_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected error
+	}{
+		{name: "empty path", path: " / ", expected: apperrors.ErrPathNotFound},
+		{name: "missing organization", path: "Missing Org/Engineering", expected: apperrors.ErrPathNotFound},
+		{name: "missing folder", path: "Acme Org/Engineering/Missing", expected: apperrors.ErrPathNotFound},
+		{name: "ambiguous folder", path: "Acme Org/Engineering/Shared", expected: apperrors.ErrPathAmbiguous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveFolderPath(context.Background(), newResolveClient(), tt.path)
+
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,688 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// DefaultResourceManagerURL is the Cloud Resource Manager endpoint used by the REST backend
+const DefaultResourceManagerURL = "https://cloudresourcemanager.googleapis.com"
+
+// DefaultServiceUsageURL is the Service Usage endpoint used to list the enabled services of a project
+const DefaultServiceUsageURL = "https://serviceusage.googleapis.com"
+
+// DefaultBillingURL is the Cloud Billing endpoint used to read the billing linkage of a project
+const DefaultBillingURL = "https://cloudbilling.googleapis.com"
+
+// tokenLifetime is how long a token fetched from gcloud is reused before asking for a new one.
+// Access tokens are valid for one hour, so refresh well before they expire.
+const tokenLifetime = 45 * time.Minute
+
+// operationPollInterval is the delay between two polls of a long running operation
+var operationPollInterval = time.Second
+
+// TokenSource returns an OAuth access token used to authorize REST calls
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenSource that always yields the given token
+func StaticToken(token string) TokenSource {
+	return func(_ context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// GCloudToken returns a TokenSource that asks gcloud for the active account's access token.
+// The token is cached, so gcloud is only spawned once per token lifetime.
+func GCloudToken(executor CommandExecutor) TokenSource {
+	var mu sync.Mutex
+	var token string
+	var fetchedAt time.Time
+
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if token != "" && time.Since(fetchedAt) < tokenLifetime {
+			return token, nil
+		}
+
+		out, err := executor.ExecuteCommand(ctx, "gcloud", "auth", "print-access-token")
+		if err != nil {
+			return "", err
+		}
+		token = strings.TrimSpace(string(out))
+		fetchedAt = time.Now()
+
+		return token, nil
+	}
+}
+
+// APIError is returned when the Resource Manager API answers with a non 2xx status
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("resource manager api: %d %s: %s", e.StatusCode, e.Status, e.Message)
+}
+
+// apiErrorClasses maps the HTTP status codes of API errors whose message has no known class
+var apiErrorClasses = map[int]error{
+	http.StatusUnauthorized:       errors.ErrUnauthenticated,
+	http.StatusForbidden:          errors.ErrPermissionDenied,
+	http.StatusNotFound:           errors.ErrNotFound,
+	http.StatusPreconditionFailed: errors.ErrFailedPrecondition,
+	http.StatusTooManyRequests:    errors.ErrQuotaExceeded,
+}
+
+// Unwrap returns the class of the failure, read from the status and message or else from the status code
+func (e *APIError) Unwrap() error {
+	if class := errors.Classify(e.Status + ": " + e.Message); class != nil {
+		return class
+	}
+
+	return apiErrorClasses[e.StatusCode]
+}
+
+// operationCodes maps the google.rpc.Code of a failed long running operation to its name and to the HTTP status
+// the API answers with for it, so operation failures are classified and retried like failed requests
+var operationCodes = map[int]struct {
+	name   string
+	status int
+}{
+	1:  {"CANCELLED", 499},
+	2:  {"UNKNOWN", http.StatusInternalServerError},
+	3:  {"INVALID_ARGUMENT", http.StatusBadRequest},
+	4:  {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+	5:  {"NOT_FOUND", http.StatusNotFound},
+	6:  {"ALREADY_EXISTS", http.StatusConflict},
+	7:  {"PERMISSION_DENIED", http.StatusForbidden},
+	8:  {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	9:  {"FAILED_PRECONDITION", http.StatusBadRequest},
+	10: {"ABORTED", http.StatusConflict},
+	11: {"OUT_OF_RANGE", http.StatusBadRequest},
+	12: {"UNIMPLEMENTED", http.StatusNotImplemented},
+	13: {"INTERNAL", http.StatusInternalServerError},
+	14: {"UNAVAILABLE", http.StatusServiceUnavailable},
+	15: {"DATA_LOSS", http.StatusInternalServerError},
+	16: {"UNAUTHENTICATED", http.StatusUnauthorized},
+}
+
+// RESTClient is the ResourceClient implementation backed by the Cloud Resource Manager v3 REST API
+type RESTClient struct {
+	baseURL         string
+	serviceUsageURL string
+	billingURL      string
+	httpClient      *http.Client
+	token           TokenSource
+	semaphore       chan struct{}
+}
+
+// NewRESTClient creates a ResourceClient talking to the given Resource Manager endpoint.
+// maxConcurrent bounds the number of in-flight requests, 0 means unbounded.
+func NewRESTClient(baseURL string, token TokenSource, maxConcurrent int) *RESTClient {
+	client := &RESTClient{
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		serviceUsageURL: DefaultServiceUsageURL,
+		billingURL:      DefaultBillingURL,
+		httpClient:      &http.Client{Timeout: time.Minute},
+		token:           token,
+	}
+	if maxConcurrent > 0 {
+		client.semaphore = make(chan struct{}, maxConcurrent)
+	}
+
+	return client
+}
+
+// WithRateLimiter makes every request wait for the given limiter
+func (c *RESTClient) WithRateLimiter(limiter *RateLimiter) *RESTClient {
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.httpClient.Transport = &rateLimitedTransport{base: base, limiter: limiter}
+
+	return c
+}
+
+type restProject struct {
+	Name        string            `json:"name"`
+	Parent      string            `json:"parent"`
+	ProjectId   string            `json:"projectId"`
+	State       string            `json:"state"`
+	DisplayName string            `json:"displayName"`
+	CreateTime  time.Time         `json:"createTime"`
+	DeleteTime  time.Time         `json:"deleteTime"`
+	Labels      map[string]string `json:"labels"`
+}
+
+func (p restProject) entry() models.Entry {
+	entry := models.NewEntry(p.ProjectId, p.DisplayName, models.EntryTypeProject)
+	entry.Number = strings.TrimPrefix(p.Name, "projects/")
+	entry.Parent = p.Parent
+	entry.LifecycleState = p.State
+	entry.CreateTime = p.CreateTime
+	entry.DeleteTime = p.DeleteTime
+	entry.Labels = p.Labels
+
+	return *entry
+}
+
+type restFolder struct {
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent"`
+	DisplayName string    `json:"displayName"`
+	State       string    `json:"state"`
+	CreateTime  time.Time `json:"createTime"`
+	DeleteTime  time.Time `json:"deleteTime"`
+}
+
+func (f restFolder) entry() models.Entry {
+	entry := models.NewEntry(strings.TrimPrefix(f.Name, "folders/"), f.DisplayName, models.EntryTypeFolder)
+	entry.Parent = f.Parent
+	entry.LifecycleState = f.State
+	entry.CreateTime = f.CreateTime
+	entry.DeleteTime = f.DeleteTime
+
+	return *entry
+}
+
+type restOrganization struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+	State       string    `json:"state"`
+	CreateTime  time.Time `json:"createTime"`
+}
+
+func (o restOrganization) entry() models.Entry {
+	entry := models.NewEntry(strings.TrimPrefix(o.Name, "organizations/"), o.DisplayName, models.EntryTypeOrganization)
+	entry.LifecycleState = o.State
+	entry.CreateTime = o.CreateTime
+
+	return *entry
+}
+
+type restOperation struct {
+	Name  string `json:"name"`
+	Done  bool   `json:"done"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type restError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// GetOrganizations lists the organizations visible to the caller
+func (c *RESTClient) GetOrganizations(ctx context.Context) ([]models.Entry, error) {
+	log := logger.New("gcp", "RESTClient.GetOrganizations")
+
+	var result []models.Entry
+	err := c.list(ctx, "/v3/organizations:search", url.Values{}, func(body []byte) (string, error) {
+		var page struct {
+			Organizations []restOrganization `json:"organizations"`
+			NextPageToken string             `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, organization := range page.Organizations {
+			result = append(result, organization.entry())
+		}
+
+		return page.NextPageToken, nil
+	})
+	if err != nil {
+		log.Error("Failed to search organizations", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"output": result,
+	})
+
+	return result, nil
+}
+
+// GetProjects lists the projects directly under the given folder or organization
+func (c *RESTClient) GetProjects(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	log := logger.New("gcp", "RESTClient.GetProjects")
+
+	var result []models.Entry
+	err := c.list(ctx, "/v3/projects", childrenQuery(parent), func(body []byte) (string, error) {
+		var page struct {
+			Projects      []restProject `json:"projects"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, project := range page.Projects {
+			result = append(result, project.entry())
+		}
+
+		return page.NextPageToken, nil
+	})
+	if err != nil {
+		log.Error("Failed to list projects", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"parent": parent.ResourceName(),
+		"output": result,
+	})
+
+	return result, nil
+}
+
+// GetFolders lists the folders directly under the given folder or organization
+func (c *RESTClient) GetFolders(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	log := logger.New("gcp", "RESTClient.GetFolders")
+
+	var result []models.Entry
+	err := c.list(ctx, "/v3/folders", childrenQuery(parent), func(body []byte) (string, error) {
+		var page struct {
+			Folders       []restFolder `json:"folders"`
+			NextPageToken string       `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		for _, folder := range page.Folders {
+			result = append(result, folder.entry())
+		}
+
+		return page.NextPageToken, nil
+	})
+	if err != nil {
+		log.Error("Failed to list folders", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"parent": parent.ResourceName(),
+		"output": result,
+	})
+
+	return result, nil
+}
+
+// DeleteProject deletes the given project and waits for the operation to finish
+func (c *RESTClient) DeleteProject(ctx context.Context, projectId string, dryRun bool) error {
+	return c.operation(ctx, "RESTClient.DeleteProject", http.MethodDelete, "/v3/projects/"+url.PathEscape(projectId), dryRun)
+}
+
+// DeleteFolder deletes the given folder and waits for the operation to finish
+func (c *RESTClient) DeleteFolder(ctx context.Context, folderId string, dryRun bool) error {
+	return c.operation(ctx, "RESTClient.DeleteFolder", http.MethodDelete, "/v3/folders/"+url.PathEscape(folderId), dryRun)
+}
+
+// GetLiens lists the liens placed on the given project
+func (c *RESTClient) GetLiens(ctx context.Context, projectId string) ([]models.Lien, error) {
+	log := logger.New("gcp", "RESTClient.GetLiens")
+
+	var result []models.Lien
+	err := c.list(ctx, "/v3/liens", url.Values{"parent": {"projects/" + projectId}}, func(body []byte) (string, error) {
+		var page struct {
+			Liens         []models.Lien `json:"liens"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		result = append(result, page.Liens...)
+
+		return page.NextPageToken, nil
+	})
+	if err != nil {
+		log.Error("Failed to list liens", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"projectId": projectId,
+		"output":    result,
+	})
+
+	return result, nil
+}
+
+// GetTags lists the effective tags of the given folder, project or organization
+func (c *RESTClient) GetTags(ctx context.Context, entry models.Entry) (map[string]string, error) {
+	log := logger.New("gcp", "RESTClient.GetTags")
+
+	var tags []effectiveTag
+	err := c.list(ctx, "/v3/effectiveTags", url.Values{"parent": {tagParent(entry)}}, func(body []byte) (string, error) {
+		var page struct {
+			EffectiveTags []effectiveTag `json:"effectiveTags"`
+			NextPageToken string         `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		tags = append(tags, page.EffectiveTags...)
+
+		return page.NextPageToken, nil
+	})
+	if err != nil {
+		log.Error("Failed to list tags", err)
+		return nil, err
+	}
+
+	result := tagMap(tags)
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"entry":  entry.ResourceName(),
+		"output": result,
+	})
+
+	return result, nil
+}
+
+// DeleteLien removes the lien with the given resource name, e.g. liens/p123-abc.
+// Unlike projects and folders, liens are deleted synchronously.
+func (c *RESTClient) DeleteLien(ctx context.Context, name string, dryRun bool) error {
+	log := logger.New("gcp", "RESTClient.DeleteLien")
+	path := "/v3/liens/" + url.PathEscape(strings.TrimPrefix(name, "liens/"))
+	log.DebugWithExtra("RESTClient.DeleteLien", map[string]any{
+		"method": http.MethodDelete,
+		"path":   path,
+	})
+	if dryRun {
+		return nil
+	}
+
+	if _, err := c.do(ctx, http.MethodDelete, path); err != nil {
+		log.Error("Failed to call the Resource Manager API", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetIAMPolicy returns the IAM policy of the given project as returned by the API
+func (c *RESTClient) GetIAMPolicy(ctx context.Context, projectId string) (json.RawMessage, error) {
+	log := logger.New("gcp", "RESTClient.GetIAMPolicy")
+
+	body, err := c.send(ctx, http.MethodPost, c.baseURL+"/v3/projects/"+url.PathEscape(projectId)+":getIamPolicy", []byte("{}"))
+	if err != nil {
+		log.Error("Failed to get IAM policy", err)
+		return nil, err
+	}
+
+	return json.RawMessage(body), nil
+}
+
+// GetEnabledServices lists the names of the services enabled on the given project
+func (c *RESTClient) GetEnabledServices(ctx context.Context, projectId string) ([]string, error) {
+	log := logger.New("gcp", "RESTClient.GetEnabledServices")
+
+	var result []string
+	query := url.Values{"filter": {"state:ENABLED"}, "pageSize": {"200"}}
+	for {
+		body, err := c.send(ctx, http.MethodGet, c.serviceUsageURL+"/v1/projects/"+url.PathEscape(projectId)+"/services?"+query.Encode(), nil)
+		if err != nil {
+			log.Error("Failed to list enabled services", err)
+			return nil, err
+		}
+
+		var page struct {
+			Services      []gcloudService `json:"services"`
+			NextPageToken string          `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to decode services: %w", err)
+		}
+		for _, service := range page.Services {
+			result = append(result, service.Config.Name)
+		}
+
+		if page.NextPageToken == "" {
+			return result, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// GetBillingInfo returns the billing account linked to the given project
+func (c *RESTClient) GetBillingInfo(ctx context.Context, projectId string) (models.BillingInfo, error) {
+	log := logger.New("gcp", "RESTClient.GetBillingInfo")
+
+	body, err := c.send(ctx, http.MethodGet, c.billingURL+"/v1/projects/"+url.PathEscape(projectId)+"/billingInfo", nil)
+	if err != nil {
+		log.Error("Failed to get billing info", err)
+		return models.BillingInfo{}, err
+	}
+
+	var billing models.BillingInfo
+	if err := json.Unmarshal(body, &billing); err != nil {
+		return models.BillingInfo{}, fmt.Errorf("failed to decode billing info: %w", err)
+	}
+
+	return billing, nil
+}
+
+// CheckHealth verifies that a token can be obtained and the API is reachable with it
+func (c *RESTClient) CheckHealth(ctx context.Context) error {
+	log := logger.New("gcp", "RESTClient.CheckHealth")
+
+	body, err := c.do(ctx, http.MethodGet, "/v3/projects:search?pageSize=1")
+	if err != nil {
+		log.Error("Failed to reach the Resource Manager API", err)
+		return fmt.Errorf("failed to reach the Resource Manager API: %w", err)
+	}
+
+	log.DebugWithExtra("Resource Manager response", map[string]any{
+		"output": string(body),
+	})
+
+	return nil
+}
+
+// GetLifecycleState returns the current state of the given folder or project
+func (c *RESTClient) GetLifecycleState(ctx context.Context, entry models.Entry) (string, error) {
+	log := logger.New("gcp", "RESTClient.GetLifecycleState")
+
+	var path string
+	switch entry.Type {
+	case models.EntryTypeProject:
+		path = "/v3/projects/" + url.PathEscape(entry.Id)
+	case models.EntryTypeFolder:
+		path = "/v3/folders/" + url.PathEscape(entry.Id)
+	default:
+		return "", fmt.Errorf("cannot describe %s", entry.ResourceName())
+	}
+
+	body, err := c.do(ctx, http.MethodGet, path)
+	if err != nil {
+		log.Error("Failed to get "+entry.ResourceName(), err)
+		return "", err
+	}
+
+	return decodeLifecycleState(body)
+}
+
+// UndeleteProject restores a project pending deletion and waits for the operation to finish
+func (c *RESTClient) UndeleteProject(ctx context.Context, projectId string, dryRun bool) error {
+	return c.operation(ctx, "RESTClient.UndeleteProject", http.MethodPost, "/v3/projects/"+url.PathEscape(projectId)+":undelete", dryRun)
+}
+
+// UndeleteFolder restores a folder pending deletion and waits for the operation to finish
+func (c *RESTClient) UndeleteFolder(ctx context.Context, folderId string, dryRun bool) error {
+	return c.operation(ctx, "RESTClient.UndeleteFolder", http.MethodPost, "/v3/folders/"+url.PathEscape(folderId)+":undelete", dryRun)
+}
+
+// operation starts a long running operation and waits for it to finish. POST requests are sent with an empty JSON body.
+func (c *RESTClient) operation(ctx context.Context, action, method, path string, dryRun bool) error {
+	log := logger.New("gcp", action)
+	log.DebugWithExtra(action, map[string]any{
+		"method": method,
+		"path":   path,
+	})
+
+	if dryRun {
+		return nil
+	}
+
+	var payload []byte
+	if method == http.MethodPost {
+		payload = []byte("{}")
+	}
+	body, err := c.send(ctx, method, c.baseURL+path, payload)
+	if err != nil {
+		log.Error("Failed to call the Resource Manager API", err)
+		return err
+	}
+
+	if err := c.wait(ctx, body); err != nil {
+		log.Error("Operation failed", err)
+		return err
+	}
+
+	return nil
+}
+
+// childrenQuery lists the children of parent, including the ones pending deletion so their state is known
+func childrenQuery(parent models.Entry) url.Values {
+	return url.Values{
+		"parent":      {parent.ResourceName()},
+		"showDeleted": {"true"},
+	}
+}
+
+// list follows nextPageToken until every page matching query has been handed to handlePage
+func (c *RESTClient) list(ctx context.Context, path string, query url.Values, handlePage func(body []byte) (string, error)) error {
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		body, err := c.do(ctx, http.MethodGet, path+"?"+query.Encode())
+		if err != nil {
+			return err
+		}
+
+		pageToken, err = handlePage(body)
+		if err != nil {
+			return err
+		}
+		if pageToken == "" {
+			return nil
+		}
+	}
+}
+
+// wait polls the long running operation described by body until it is done
+func (c *RESTClient) wait(ctx context.Context, body []byte) error {
+	var op restOperation
+	if err := json.Unmarshal(body, &op); err != nil {
+		return err
+	}
+
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(operationPollInterval):
+		}
+
+		body, err := c.do(ctx, http.MethodGet, "/v3/"+op.Name)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &op); err != nil {
+			return err
+		}
+	}
+
+	if op.Error != nil {
+		code, known := operationCodes[op.Error.Code]
+		if !known {
+			code = operationCodes[2]
+		}
+		return &APIError{StatusCode: code.status, Status: code.name, Message: op.Error.Message}
+	}
+
+	return nil
+}
+
+// do calls the Resource Manager API
+func (c *RESTClient) do(ctx context.Context, method, path string) ([]byte, error) {
+	return c.send(ctx, method, c.baseURL+path, nil)
+}
+
+// send calls any Google API with the client's token, body is sent as JSON when not nil
+func (c *RESTClient) send(ctx context.Context, method, rawURL string, payload []byte) ([]byte, error) {
+	if c.semaphore != nil {
+		select {
+		case c.semaphore <- struct{}{}:
+			defer func() { <-c.semaphore }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	var requestBody io.Reader
+	if payload != nil {
+		requestBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, requestBody)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Status: http.StatusText(resp.StatusCode), Message: strings.TrimSpace(string(body))}
+		var decoded restError
+		if json.Unmarshal(body, &decoded) == nil && decoded.Error.Message != "" {
+			apiErr.Status = decoded.Error.Status
+			apiErr.Message = decoded.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	return body, nil
+}
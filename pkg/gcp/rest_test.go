@@ -0,0 +1,502 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+var testFolder = *models.NewEntry("12345", "12345", models.EntryTypeFolder)
+
+// fakeResourceManager is an httptest stand-in for the Cloud Resource Manager v3 API
+type fakeResourceManager struct {
+	mu       sync.Mutex
+	requests []string
+	handlers map[string]http.HandlerFunc
+}
+
+func newFakeResourceManager(t *testing.T) (*fakeResourceManager, *httptest.Server) {
+	fake := &fakeResourceManager{handlers: map[string]http.HandlerFunc{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, r.Method+" "+r.URL.RequestURI())
+		handler, ok := fake.handlers[r.Method+" "+r.URL.Path]
+		fake.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"code":401,"message":"missing token","status":"UNAUTHENTICATED"}}`))
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"not found","status":"NOT_FOUND"}}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+func (f *fakeResourceManager) handle(pattern string, body string) {
+	f.handlers[pattern] = func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestRESTClient_GetProjects_Pagination(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["GET /v3/projects"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("parent") != "folders/12345" {
+			t.Errorf("Expected parent folders/12345, got %s", r.URL.Query().Get("parent"))
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"projects":[{"name":"projects/1","projectId":"project-1","displayName":"Project, 1"}],"nextPageToken":"next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"projects":[{"name":"projects/2","projectId":"project-2","displayName":"Project 2"}]}`))
+	}
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	projects, err := client.GetProjects(context.Background(), testFolder)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects))
+	}
+
+	if projects[0].Id != "project-1" || projects[0].Name != "Project, 1" {
+		t.Errorf("Unexpected first project %+v", projects[0])
+	}
+
+	if projects[1].Id != "project-2" || projects[1].Name != "Project 2" {
+		t.Errorf("Unexpected second project %+v", projects[1])
+	}
+
+	if len(fake.requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(fake.requests))
+	}
+}
+
+func TestRESTClient_GetFolders_Success(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("GET /v3/folders", `{"folders":[{"name":"folders/111","displayName":"Folder 1"},{"name":"folders/222","displayName":"Folder 2"}]}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	folders, err := client.GetFolders(context.Background(), testFolder)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(folders) != 2 {
+		t.Fatalf("Expected 2 folders, got %d", len(folders))
+	}
+
+	if folders[0].Id != "111" || folders[0].Name != "Folder 1" {
+		t.Errorf("Unexpected first folder %+v", folders[0])
+	}
+}
+
+func TestRESTClient_GetFolders_EmptyResult(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("GET /v3/folders", `{}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	folders, err := client.GetFolders(context.Background(), testFolder)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if folders != nil {
+		t.Errorf("Expected nil folders for empty response, got %v", folders)
+	}
+}
+
+func TestRESTClient_APIError(t *testing.T) {
+	_, server := newFakeResourceManager(t)
+
+	client := NewRESTClient(server.URL, StaticToken("wrong-token"), 0)
+	_, err := client.GetFolders(context.Background(), testFolder)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Status != "UNAUTHENTICATED" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+}
+
+func TestRESTClient_TokenError(t *testing.T) {
+	_, server := newFakeResourceManager(t)
+
+	client := NewRESTClient(server.URL, func(_ context.Context) (string, error) {
+		return "", errors.New("no credentials")
+	}, 0)
+	_, err := client.GetProjects(context.Background(), testFolder)
+
+	if err == nil {
+		t.Error("Expected error when the token cannot be obtained, got nil")
+	}
+}
+
+func TestRESTClient_DeleteProject_WaitsForOperation(t *testing.T) {
+	operationPollInterval = time.Millisecond
+	fake, server := newFakeResourceManager(t)
+	fake.handle("DELETE /v3/projects/test-project", `{"name":"operations/op-1","done":false}`)
+	fake.handle("GET /v3/operations/op-1", `{"name":"operations/op-1","done":true}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	err := client.DeleteProject(context.Background(), "test-project", false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"DELETE /v3/projects/test-project", "GET /v3/operations/op-1"}
+	if len(fake.requests) != len(expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, fake.requests)
+	}
+	for i, request := range fake.requests {
+		if request != expected[i] {
+			t.Errorf("Expected request[%d] to be %s, got %s", i, expected[i], request)
+		}
+	}
+}
+
+func TestRESTClient_DeleteFolder_OperationError(t *testing.T) {
+	operationPollInterval = time.Millisecond
+	tests := []struct {
+		name      string
+		error     string
+		class     error
+		transient bool
+	}{
+		{"not empty", `{"code":9,"message":"Folder is not empty"}`, apperrors.ErrFailedPrecondition, false},
+		{"failed precondition", `{"code":9,"message":"Folder cannot be deleted"}`, apperrors.ErrFailedPrecondition, false},
+		{"permission denied", `{"code":7,"message":"Caller lacks permission"}`, apperrors.ErrPermissionDenied, false},
+		{"resource exhausted", `{"code":8,"message":"Too many deletions"}`, apperrors.ErrQuotaExceeded, true},
+		{"unavailable", `{"code":14,"message":"Backend went away"}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, server := newFakeResourceManager(t)
+			fake.handle("DELETE /v3/folders/111", `{"name":"operations/op-2","done":false}`)
+			fake.handle("GET /v3/operations/op-2", `{"name":"operations/op-2","done":true,"error":`+tt.error+`}`)
+
+			client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+			err := client.DeleteFolder(context.Background(), "111", false)
+
+			if err == nil {
+				t.Fatal("Expected error when the operation fails, got nil")
+			}
+			if class := apperrors.ClassOf(err); class != tt.class {
+				t.Errorf("Expected class %v, got %v (%v)", tt.class, class, err)
+			}
+			if IsTransient(err) != tt.transient {
+				t.Errorf("Expected transient to be %v, got %v (%v)", tt.transient, !tt.transient, err)
+			}
+		})
+	}
+}
+
+func TestRESTClient_DeleteFolder_DryRun(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	err := client.DeleteFolder(context.Background(), "111", true)
+
+	if err != nil {
+		t.Errorf("Expected no error in dry run mode, got %v", err)
+	}
+
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected 0 requests in dry run mode, got %d", len(fake.requests))
+	}
+}
+
+func TestGCloudToken_Cached(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte("test-token\n"),
+	}
+
+	source := GCloudToken(mockExec)
+	for i := 0; i < 3; i++ {
+		token, err := source(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if token != "test-token" {
+			t.Errorf("Expected token 'test-token', got %q", token)
+		}
+	}
+
+	if mockExec.GetCallCount() != 1 {
+		t.Errorf("Expected gcloud to be called once, got %d", mockExec.GetCallCount())
+	}
+}
+
+func TestRESTClient_GetFolders_Organization(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["GET /v3/folders"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("parent") != "organizations/999" {
+			t.Errorf("Expected parent organizations/999, got %s", r.URL.Query().Get("parent"))
+		}
+		_, _ = w.Write([]byte(`{"folders":[{"name":"folders/111","displayName":"Top Level","parent":"organizations/999"}]}`))
+	}
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	folders, err := client.GetFolders(context.Background(), *models.NewEntry("999", "Acme", models.EntryTypeOrganization))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(folders) != 1 || folders[0].Parent != "organizations/999" {
+		t.Errorf("Unexpected folders %+v", folders)
+	}
+}
+
+func TestRESTClient_GetProjects_PendingDeletion(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["GET /v3/projects"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("showDeleted") != "true" {
+			t.Errorf("Expected showDeleted=true, got %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"projects":[{"name":"projects/1","projectId":"project-1","state":"DELETE_REQUESTED","deleteTime":"2024-01-01T00:00:00Z"}]}`))
+	}
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	projects, err := client.GetProjects(context.Background(), testFolder)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(projects) != 1 || !projects[0].PendingDeletion() {
+		t.Fatalf("Expected 1 project pending deletion, got %+v", projects)
+	}
+
+	purgeTime, known := projects[0].PurgeTime()
+	if !known || !purgeTime.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected purge time 2024-01-31, got %v", purgeTime)
+	}
+}
+
+func TestRESTClient_Liens(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["GET /v3/liens"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("parent") != "projects/test-project" {
+			t.Errorf("Expected parent projects/test-project, got %s", r.URL.Query().Get("parent"))
+		}
+		_, _ = w.Write([]byte(`{"liens":[{"name":"liens/p1-abc","reason":"Managed by Service Networking","origin":"servicenetworking.googleapis.com","restrictions":["resourcemanager.projects.delete"]}]}`))
+	}
+	fake.handle("DELETE /v3/liens/p1-abc", `{}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	liens, err := client.GetLiens(context.Background(), "test-project")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(liens) != 1 || liens[0].Name != "liens/p1-abc" || liens[0].Origin != "servicenetworking.googleapis.com" {
+		t.Fatalf("Unexpected liens %+v", liens)
+	}
+
+	if err := client.DeleteLien(context.Background(), liens[0].Name, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if last := fake.requests[len(fake.requests)-1]; last != "DELETE /v3/liens/p1-abc" {
+		t.Errorf("Expected DELETE /v3/liens/p1-abc, got %s", last)
+	}
+}
+
+func TestRESTClient_ProjectMetadata(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("POST /v3/projects/test-project:getIamPolicy", `{"bindings":[{"role":"roles/owner","members":["user:a@example.com"]}]}`)
+	fake.handlers["GET /v1/projects/test-project/services"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"services":[{"config":{"name":"compute.googleapis.com"}}],"nextPageToken":"next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"services":[{"config":{"name":"storage.googleapis.com"}}]}`))
+	}
+	fake.handle("GET /v1/projects/test-project/billingInfo", `{"billingAccountName":"billingAccounts/0000-1111","billingEnabled":true}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	client.serviceUsageURL = server.URL
+	client.billingURL = server.URL
+	ctx := context.Background()
+
+	policy, err := client.GetIAMPolicy(ctx, "test-project")
+	if err != nil || !strings.Contains(string(policy), "roles/owner") {
+		t.Errorf("Expected IAM policy, got %s (%v)", policy, err)
+	}
+
+	services, err := client.GetEnabledServices(ctx, "test-project")
+	if err != nil || len(services) != 2 || services[1] != "storage.googleapis.com" {
+		t.Errorf("Expected 2 services, got %v (%v)", services, err)
+	}
+
+	billing, err := client.GetBillingInfo(ctx, "test-project")
+	if err != nil || billing.AccountName != "billingAccounts/0000-1111" || !billing.Enabled {
+		t.Errorf("Expected billing info, got %+v (%v)", billing, err)
+	}
+}
+
+func TestRESTClient_Undelete(t *testing.T) {
+	operationPollInterval = time.Millisecond
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["POST /v3/folders/111:undelete"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON body, got content type %q", r.Header.Get("Content-Type"))
+		}
+		_, _ = w.Write([]byte(`{"name":"operations/op-3","done":false}`))
+	}
+	fake.handle("GET /v3/operations/op-3", `{"name":"operations/op-3","done":true}`)
+	fake.handle("POST /v3/projects/test-project:undelete", `{"name":"operations/op-4","done":true}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	if err := client.UndeleteFolder(context.Background(), "111", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.UndeleteProject(context.Background(), "test-project", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"POST /v3/folders/111:undelete", "GET /v3/operations/op-3", "POST /v3/projects/test-project:undelete"}
+	if !slices.Equal(fake.requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, fake.requests)
+	}
+}
+
+func TestRESTClient_GetLifecycleState(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("GET /v3/projects/test-project", `{"name":"projects/1","projectId":"test-project","state":"DELETE_REQUESTED"}`)
+	fake.handle("GET /v3/folders/111", `{"name":"folders/111","state":"ACTIVE"}`)
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	ctx := context.Background()
+
+	state, err := client.GetLifecycleState(ctx, *models.NewEntry("test-project", "Test", models.EntryTypeProject))
+	if err != nil || state != models.LifecycleStateDeleteRequested {
+		t.Errorf("Expected DELETE_REQUESTED, got %s (%v)", state, err)
+	}
+
+	state, err = client.GetLifecycleState(ctx, *models.NewEntry("111", "Folder", models.EntryTypeFolder))
+	if err != nil || state != models.LifecycleStateActive {
+		t.Errorf("Expected ACTIVE, got %s (%v)", state, err)
+	}
+}
+
+func TestRESTClient_GetTags(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handlers["GET /v3/effectiveTags"] = func(w http.ResponseWriter, r *http.Request) {
+		if parent := r.URL.Query().Get("parent"); parent != "//cloudresourcemanager.googleapis.com/folders/12345" {
+			t.Errorf("Expected the folder as parent, got %s", parent)
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"effectiveTags":[{"namespacedTagKey":"123/env","namespacedTagValue":"123/env/prod"}],"nextPageToken":"next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"effectiveTags":[{"namespacedTagKey":"123/team","namespacedTagValue":"123/team/data","inherited":true}]}`))
+	}
+
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0)
+	tags, err := client.GetTags(context.Background(), testFolder)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tags) != 2 || tags["123/env"] != "prod" || tags["123/team"] != "data" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+}
+
+func TestRESTClient_CheckHealth(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("GET /v3/projects:search", `{"projects":[]}`)
+
+	if err := NewRESTClient(server.URL, StaticToken("test-token"), 0).CheckHealth(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err := NewRESTClient(server.URL, StaticToken("wrong-token"), 0).CheckHealth(context.Background())
+	if !errors.Is(err, apperrors.ErrUnauthenticated) {
+		t.Errorf("Expected an unauthenticated error, got %v", err)
+	}
+}
+
+func TestAPIError_Class(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected error
+	}{
+		{"status", &APIError{StatusCode: http.StatusForbidden, Status: "PERMISSION_DENIED", Message: "The caller does not have permission"}, apperrors.ErrPermissionDenied},
+		{"status code", &APIError{StatusCode: http.StatusNotFound, Status: "Not Found"}, apperrors.ErrNotFound},
+		{"operation", &APIError{StatusCode: 9, Status: "operation failed", Message: "Folder is not empty"}, apperrors.ErrFailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.expected) {
+				t.Errorf("Expected %v to be %v", tt.err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRESTClient_RateLimiter(t *testing.T) {
+	fake, server := newFakeResourceManager(t)
+	fake.handle("GET /v3/folders", `{}`)
+	fake.handle("DELETE /v3/folders/111", `{"done":true}`)
+
+	limiter := NewRateLimiter(0, 100)
+	// Freeze the clock of the write bucket, so slow requests cannot refill it while the test runs
+	frozen := time.Now()
+	limiter.write.now = func() time.Time { return frozen }
+	limiter.write.last = frozen
+	client := NewRESTClient(server.URL, StaticToken("test-token"), 0).WithRateLimiter(limiter)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetFolders(ctx, testFolder); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if limiter.Waited() != 0 {
+		t.Errorf("Expected unlimited reads not to wait, waited %s", limiter.Waited())
+	}
+
+	for i := 0; i < 102; i++ {
+		if err := client.DeleteFolder(ctx, "111", false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	// The two deletes beyond the burst of 100 wait for one and two refills of 10ms
+	if waited := limiter.Waited(); waited != 30*time.Millisecond {
+		t.Errorf("Expected deletes beyond the burst to wait 30ms, waited %s", waited)
+	}
+}
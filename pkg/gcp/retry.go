@@ -0,0 +1,221 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// RetryPolicy controls how list and delete calls that failed with a transient error are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one, 1 disables retries
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry, it doubles with every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between two attempts, 0 leaves it uncapped
+	MaxDelay time.Duration
+	// Jitter is the fraction of each wait that is randomized, between 0 and 1
+	Jitter float64
+}
+
+// DefaultRetryPolicy retries a call three times, waiting about 1s, 2s and 4s
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 30 * time.Second, Jitter: 0.2}
+
+// Delay returns the wait before the given retry, counted from 1. random returns a value in [0, 1).
+func (p RetryPolicy) Delay(retry int, random func() float64) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+
+	return delay - time.Duration(float64(delay)*jitter*random())
+}
+
+// transientMarkers are lower case fragments of gcloud output that point to a temporary failure
+// other than an exceeded quota, which is recognized by its class
+var transientMarkers = []string{
+	"'status': '429'",
+	"'status': '503'",
+	"unavailable",
+	"deadline_exceeded",
+	"deadline exceeded",
+	"connection reset",
+	"connection aborted",
+	"timed out",
+}
+
+// IsTransient reports whether err is worth retrying: throttling, unavailability, timeouts and connection resets
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, apperrors.ErrQuotaExceeded) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var commandErr *CommandError
+	if errors.As(err, &commandErr) {
+		output := strings.ToLower(string(commandErr.Output))
+		for _, marker := range transientMarkers {
+			if strings.Contains(output, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RetryingClient retries the list and delete calls of the wrapped client on transient errors.
+// All other calls are passed through unchanged.
+type RetryingClient struct {
+	ResourceClient
+
+	policy  RetryPolicy
+	random  func() float64
+	retries atomic.Int64
+}
+
+func NewRetryingClient(client ResourceClient, policy RetryPolicy) *RetryingClient {
+	return &RetryingClient{ResourceClient: client, policy: policy, random: rand.Float64}
+}
+
+// Retries returns the number of retried calls so far
+func (c *RetryingClient) Retries() int {
+	return int(c.retries.Load())
+}
+
+// GetOrganizations lists the organizations, retrying transient failures
+func (c *RetryingClient) GetOrganizations(ctx context.Context) ([]models.Entry, error) {
+	return retryValue(ctx, c, "GetOrganizations", func() ([]models.Entry, error) {
+		return c.ResourceClient.GetOrganizations(ctx)
+	})
+}
+
+// GetProjects lists the projects under parent, retrying transient failures
+func (c *RetryingClient) GetProjects(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	return retryValue(ctx, c, "GetProjects "+parent.ResourceName(), func() ([]models.Entry, error) {
+		return c.ResourceClient.GetProjects(ctx, parent)
+	})
+}
+
+// GetFolders lists the folders under parent, retrying transient failures
+func (c *RetryingClient) GetFolders(ctx context.Context, parent models.Entry) ([]models.Entry, error) {
+	return retryValue(ctx, c, "GetFolders "+parent.ResourceName(), func() ([]models.Entry, error) {
+		return c.ResourceClient.GetFolders(ctx, parent)
+	})
+}
+
+// DeleteProject deletes the project, retrying transient failures
+func (c *RetryingClient) DeleteProject(ctx context.Context, projectId string, dryRun bool) error {
+	return c.retryDelete(ctx, *models.NewEntry(projectId, "", models.EntryTypeProject), func() error {
+		return c.ResourceClient.DeleteProject(ctx, projectId, dryRun)
+	})
+}
+
+// DeleteFolder deletes the folder, retrying transient failures
+func (c *RetryingClient) DeleteFolder(ctx context.Context, folderId string, dryRun bool) error {
+	return c.retryDelete(ctx, *models.NewEntry(folderId, "", models.EntryTypeFolder), func() error {
+		return c.ResourceClient.DeleteFolder(ctx, folderId, dryRun)
+	})
+}
+
+// retryDelete retries the deletion of entry like retry, but checks its state before deleting it again.
+// A failed attempt may have been accepted before it failed, e.g. while its operation was polled, and
+// deleting an entry already pending deletion fails, so that state counts as success.
+func (c *RetryingClient) retryDelete(ctx context.Context, entry models.Entry, call func() error) error {
+	log := logger.New("gcp", "RetryingClient")
+
+	attempt := 0
+	return c.retry(ctx, "Delete "+entry.ResourceName(), func() error {
+		attempt++
+		if attempt > 1 {
+			state, err := c.ResourceClient.GetLifecycleState(ctx, entry)
+			if err == nil && state == models.LifecycleStateDeleteRequested {
+				log.Info("Deletion of " + entry.ResourceName() + " was already requested by a failed attempt")
+				return nil
+			}
+		}
+		return call()
+	})
+}
+
+// GetLiens lists the liens of the project, retrying transient failures
+func (c *RetryingClient) GetLiens(ctx context.Context, projectId string) ([]models.Lien, error) {
+	return retryValue(ctx, c, "GetLiens "+projectId, func() ([]models.Lien, error) {
+		return c.ResourceClient.GetLiens(ctx, projectId)
+	})
+}
+
+// GetTags lists the effective tags of the entry, retrying transient failures
+func (c *RetryingClient) GetTags(ctx context.Context, entry models.Entry) (map[string]string, error) {
+	return retryValue(ctx, c, "GetTags "+entry.ResourceName(), func() (map[string]string, error) {
+		return c.ResourceClient.GetTags(ctx, entry)
+	})
+}
+
+// DeleteLien removes the lien, retrying transient failures
+func (c *RetryingClient) DeleteLien(ctx context.Context, name string, dryRun bool) error {
+	return c.retry(ctx, "DeleteLien "+name, func() error {
+		return c.ResourceClient.DeleteLien(ctx, name, dryRun)
+	})
+}
+
+// retry runs call until it succeeds, fails with a permanent error or runs out of attempts
+func (c *RetryingClient) retry(ctx context.Context, action string, call func() error) error {
+	log := logger.New("gcp", "RetryingClient")
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= c.policy.MaxAttempts || !IsTransient(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.policy.Delay(attempt, c.random)
+		log.Warn(fmt.Sprintf("%s failed with a transient error, retrying in %s (attempt %d of %d): %v",
+			action, delay.Round(time.Millisecond), attempt+1, c.policy.MaxAttempts, err))
+		c.retries.Add(1)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func retryValue[T any](ctx context.Context, c *RetryingClient, action string, call func() (T, error)) (T, error) {
+	var result T
+	err := c.retry(ctx, action, func() error {
+		var err error
+		result, err = call()
+		return err
+	})
+
+	return result, err
+}
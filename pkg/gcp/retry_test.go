@@ -0,0 +1,169 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// flakyClient fails the first failures calls of DeleteProject and GetFolders with err.
+// Its entries are in state, or active when it is empty.
+type flakyClient struct {
+	fakeClient
+	failures int
+	err      error
+	calls    int
+	state    string
+}
+
+func (f *flakyClient) GetLifecycleState(ctx context.Context, entry models.Entry) (string, error) {
+	if f.state != "" {
+		return f.state, nil
+	}
+	return f.fakeClient.GetLifecycleState(ctx, entry)
+}
+
+func (f *flakyClient) DeleteProject(_ context.Context, _ string, _ bool) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyClient) GetFolders(_ context.Context, _ models.Entry) ([]models.Entry, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return []models.Entry{*models.NewEntry("111", "Folder", models.EntryTypeFolder)}, nil
+}
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Jitter: 0.5}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline exceeded", fmt.Errorf("list: %w", context.DeadlineExceeded), true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"api 429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"api 503", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"api 403", &APIError{StatusCode: http.StatusForbidden}, false},
+		{"gcloud quota", &CommandError{Err: &exec.ExitError{}, Output: []byte("ERROR: (gcloud.projects.delete) RESOURCE_EXHAUSTED: Quota exceeded for quota metric 'Delete requests'")}, true},
+		{"gcloud unavailable", &CommandError{Err: &exec.ExitError{}, Output: []byte("ERROR: (gcloud.projects.list) UNAVAILABLE: The service is currently unavailable.")}, true},
+		{"gcloud permission denied", &CommandError{Err: &exec.ExitError{}, Output: []byte("ERROR: (gcloud.projects.delete) PERMISSION_DENIED: Permission denied on resource project p1.")}, false},
+		{"plain error", errors.New("permission denied"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsTransient(tt.err); actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second, Jitter: 0.5}
+	noJitter := func() float64 { return 0 }
+	fullJitter := func() float64 { return 1 }
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, delay := range expected {
+		if actual := policy.Delay(i+1, noJitter); actual != delay {
+			t.Errorf("Expected retry %d to wait %s, got %s", i+1, delay, actual)
+		}
+	}
+
+	if actual := policy.Delay(2, fullJitter); actual != time.Second {
+		t.Errorf("Expected jitter to halve the delay to 1s, got %s", actual)
+	}
+
+	uncapped := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second}
+	if actual := uncapped.Delay(6, noJitter); actual != 32*time.Second {
+		t.Errorf("Expected a zero maximum delay to keep doubling to 32s, got %s", actual)
+	}
+}
+
+func TestRetryingClient_RetriesTransientErrors(t *testing.T) {
+	inner := &flakyClient{failures: 2, err: &APIError{StatusCode: http.StatusTooManyRequests}}
+	client := NewRetryingClient(inner, testRetryPolicy)
+
+	if err := client.DeleteProject(context.Background(), "p1", false); err != nil {
+		t.Fatalf("Expected no error after retries, got %v", err)
+	}
+
+	if inner.calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", inner.calls)
+	}
+
+	if client.Retries() != 2 {
+		t.Errorf("Expected 2 retries, got %d", client.Retries())
+	}
+}
+
+func TestRetryingClient_DeleteAlreadyRequested(t *testing.T) {
+	// The first delete was accepted, but polling its operation failed
+	inner := &flakyClient{failures: 1, err: &APIError{StatusCode: http.StatusServiceUnavailable}, state: models.LifecycleStateDeleteRequested}
+	client := NewRetryingClient(inner, testRetryPolicy)
+
+	if err := client.DeleteProject(context.Background(), "p1", false); err != nil {
+		t.Fatalf("Expected no error once deletion was requested, got %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("Expected the delete not to be sent again, got %d calls", inner.calls)
+	}
+}
+
+func TestRetryingClient_GivesUp(t *testing.T) {
+	inner := &flakyClient{failures: 5, err: &APIError{StatusCode: http.StatusServiceUnavailable}}
+	client := NewRetryingClient(inner, testRetryPolicy)
+
+	folders, err := client.GetFolders(context.Background(), testFolder)
+
+	if err == nil || folders != nil {
+		t.Fatalf("Expected error after %d attempts, got %v", testRetryPolicy.MaxAttempts, folders)
+	}
+
+	if inner.calls != testRetryPolicy.MaxAttempts {
+		t.Errorf("Expected %d calls, got %d", testRetryPolicy.MaxAttempts, inner.calls)
+	}
+}
+
+func TestRetryingClient_PermanentError(t *testing.T) {
+	inner := &flakyClient{failures: 1, err: &APIError{StatusCode: http.StatusForbidden}}
+	client := NewRetryingClient(inner, testRetryPolicy)
+
+	if err := client.DeleteProject(context.Background(), "p1", false); err == nil {
+		t.Fatal("Expected permanent error, got nil")
+	}
+
+	if inner.calls != 1 || client.Retries() != 0 {
+		t.Errorf("Expected a single call without retries, got %d calls and %d retries", inner.calls, client.Retries())
+	}
+}
+
+func TestRetryingClient_ListRetry(t *testing.T) {
+	inner := &flakyClient{failures: 1, err: context.DeadlineExceeded}
+	client := NewRetryingClient(inner, testRetryPolicy)
+
+	folders, err := client.GetFolders(context.Background(), testFolder)
+
+	if err != nil || len(folders) != 1 {
+		t.Errorf("Expected 1 folder after a retry, got %v (%v)", folders, err)
+	}
+}
@@ -0,0 +1,46 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// GetLifecycleState returns the current lifecycle state of the given folder or project
+func GetLifecycleState(rootCtx context.Context, entry models.Entry, executor CommandExecutor) (string, error) {
+	var args []string
+	switch entry.Type {
+	case models.EntryTypeProject:
+		args = []string{"projects", "describe", entry.Id, "--format", "json"}
+	case models.EntryTypeFolder:
+		args = []string{"resource-manager", "folders", "describe", entry.Id, "--format", "json"}
+	default:
+		return "", fmt.Errorf("cannot describe %s", entry.ResourceName())
+	}
+
+	out, err := runMetadataCommand(rootCtx, "GetLifecycleState", executor, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return decodeLifecycleState(out)
+}
+
+// decodeLifecycleState reads the state of a single project or folder described by gcloud or the API,
+// which report it as lifecycleState (v1 and v2) or state (v3)
+func decodeLifecycleState(out []byte) (string, error) {
+	var resource struct {
+		LifecycleState string `json:"lifecycleState"`
+		State          string `json:"state"`
+	}
+	if err := json.Unmarshal(out, &resource); err != nil {
+		return "", fmt.Errorf("failed to decode lifecycle state: %w", err)
+	}
+	if resource.LifecycleState != "" {
+		return resource.LifecycleState, nil
+	}
+
+	return resource.State, nil
+}
@@ -0,0 +1,60 @@
+package gcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+func TestGetLifecycleState(t *testing.T) {
+	tests := []struct {
+		name         string
+		entry        models.Entry
+		output       string
+		expected     string
+		expectedArgs []string
+	}{
+		{
+			name:         "project",
+			entry:        *models.NewEntry("project1", "Project 1", models.EntryTypeProject),
+			output:       `{"projectId":"project1","lifecycleState":"DELETE_REQUESTED"}`,
+			expected:     models.LifecycleStateDeleteRequested,
+			expectedArgs: []string{"projects", "describe", "project1", "--format", "json"},
+		},
+		{
+			name:         "folder",
+			entry:        *models.NewEntry("111", "Folder", models.EntryTypeFolder),
+			output:       `{"name":"folders/111","state":"ACTIVE"}`,
+			expected:     models.LifecycleStateActive,
+			expectedArgs: []string{"resource-manager", "folders", "describe", "111", "--format", "json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExec := &MockExecutor{MockOutput: []byte(tt.output)}
+
+			state, err := GetLifecycleState(context.Background(), tt.entry, mockExec)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if state != tt.expected {
+				t.Errorf("Expected state %s, got %s", tt.expected, state)
+			}
+			if args := mockExec.GetLastCall().Args; !slices.Equal(args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestGetLifecycleState_Organization(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if _, err := GetLifecycleState(context.Background(), *models.NewEntry("999", "Acme", models.EntryTypeOrganization), mockExec); err == nil {
+		t.Error("Expected error for an organization, got nil")
+	}
+}
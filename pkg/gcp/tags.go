@@ -0,0 +1,40 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// GetTags lists the tags bound to the given entry, including the ones inherited from its ancestors
+func GetTags(rootCtx context.Context, entry models.Entry, executor CommandExecutor) (map[string]string, error) {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	args := []string{"resource-manager", "tags", "bindings", "list", "--parent", tagParent(entry), "--effective", "--format", "json"}
+
+	log := logger.New("gcp", "GetTags")
+	log.DebugWithExtra("getTags", map[string]any{
+		"cmd":  "gcloud",
+		"args": args,
+	})
+	out, err := executor.ExecuteCommand(ctx, "gcloud", args...)
+	if err != nil {
+		log.Error("Failed to run command", err)
+		return nil, err
+	}
+
+	result, err := decodeTags(out)
+	if err != nil {
+		log.Error("Failed to decode command output", err)
+		return nil, err
+	}
+
+	log.DebugWithExtra("Gcloud command output", map[string]any{
+		"entry":  entry.ResourceName(),
+		"output": result,
+	})
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+func TestGetTags_Success(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockOutput: []byte(`[{"namespacedTagKey":"123/env","namespacedTagValue":"123/env/prod","tagKey":"tagKeys/1","tagValue":"tagValues/2","inherited":true},{"namespacedTagKey":"123/team","namespacedTagValue":"123/team/data","tagKey":"tagKeys/3","tagValue":"tagValues/4"}]`),
+	}
+
+	project := *models.NewEntry("project1", "Project 1", models.EntryTypeProject)
+	project.Number = "456"
+	tags, err := GetTags(context.Background(), project, mockExec)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tags) != 2 || tags["123/env"] != "prod" || tags["123/team"] != "data" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+
+	expectedArgs := []string{"resource-manager", "tags", "bindings", "list", "--parent", "//cloudresourcemanager.googleapis.com/projects/456", "--effective", "--format", "json"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestGetTags_Folder(t *testing.T) {
+	mockExec := &MockExecutor{MockOutput: []byte(`[]`)}
+
+	tags, err := GetTags(context.Background(), *models.NewEntry("111", "Folder", models.EntryTypeFolder), mockExec)
+	if err != nil || tags != nil {
+		t.Errorf("Expected no tags, got %v (%v)", tags, err)
+	}
+
+	if parent := mockExec.GetLastCall().Args[5]; parent != "//cloudresourcemanager.googleapis.com/folders/111" {
+		t.Errorf("Expected the folder as parent, got %s", parent)
+	}
+}
+
+func TestGetTags_Error(t *testing.T) {
+	mockExec := &MockExecutor{MockError: errors.New("permission denied")}
+
+	if _, err := GetTags(context.Background(), *models.NewEntry("111", "Folder", models.EntryTypeFolder), mockExec); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
@@ -0,0 +1,38 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/logger"
+)
+
+// UndeleteProject restores a project that is pending deletion
+func UndeleteProject(rootCtx context.Context, projectId string, dryRun bool, executor CommandExecutor) error {
+	return runUndelete(rootCtx, "UndeleteProject", dryRun, executor, "projects", "undelete", projectId, "--quiet")
+}
+
+// UndeleteFolder restores a folder that is pending deletion
+func UndeleteFolder(rootCtx context.Context, folderId string, dryRun bool, executor CommandExecutor) error {
+	return runUndelete(rootCtx, "UndeleteFolder", dryRun, executor, "resource-manager", "folders", "undelete", folderId, "--quiet")
+}
+
+func runUndelete(rootCtx context.Context, action string, dryRun bool, executor CommandExecutor, args ...string) error {
+	ctx, cancelFunc := context.WithCancel(rootCtx)
+	defer cancelFunc()
+
+	log := logger.New("gcp", action)
+	log.DebugWithExtra(action, map[string]any{
+		"cmd":  "gcloud",
+		"args": args,
+	})
+	if dryRun {
+		return nil
+	}
+
+	if _, err := executor.ExecuteCommand(ctx, "gcloud", args...); err != nil {
+		log.Error("Failed to run command", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestUndeleteProject(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if err := UndeleteProject(context.Background(), "project1", false, mockExec); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedArgs := []string{"projects", "undelete", "project1", "--quiet"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestUndeleteFolder(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if err := UndeleteFolder(context.Background(), "111", false, mockExec); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedArgs := []string{"resource-manager", "folders", "undelete", "111", "--quiet"}
+	if args := mockExec.GetLastCall().Args; !slices.Equal(args, expectedArgs) {
+		t.Errorf("Expected args %v, got %v", expectedArgs, args)
+	}
+}
+
+func TestUndeleteFolder_Error(t *testing.T) {
+	mockExec := &MockExecutor{
+		MockError: errors.New("folder is not pending deletion"),
+	}
+
+	if err := UndeleteFolder(context.Background(), "111", false, mockExec); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestUndeleteProject_DryRun(t *testing.T) {
+	mockExec := &MockExecutor{}
+
+	if err := UndeleteProject(context.Background(), "project1", true, mockExec); err != nil {
+		t.Errorf("Expected no error in dry run mode, got %v", err)
+	}
+
+	if mockExec.GetCallCount() != 0 {
+		t.Errorf("Expected 0 calls in dry run mode, got %d", mockExec.GetCallCount())
+	}
+}
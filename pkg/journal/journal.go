@@ -0,0 +1,127 @@
+// Package journal appends the progress of a deletion to a JSON lines file, so an interrupted run can be resumed.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// Journal appends records to an open journal file
+type Journal struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// Open opens the journal at path for appending, creating it when missing. A truncated last line left behind
+// by a killed run is removed first, so new records start on a line of their own.
+func Open(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal %s: %w", path, err)
+	}
+	if err := repairTail(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to repair journal %s: %w", path, err)
+	}
+
+	return &Journal{file: file}, nil
+}
+
+// repairTail ends the file with a newline. A last line holding a whole record only lacks the newline,
+// any other last line was cut off while being written and is dropped.
+func repairTail(file *os.File) error {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || bytes.HasSuffix(data, []byte("\n")) {
+		return nil
+	}
+
+	start := bytes.LastIndexByte(data, '\n') + 1
+	if json.Valid(data[start:]) {
+		_, err = file.Write([]byte("\n"))
+		return err
+	}
+
+	return file.Truncate(int64(start))
+}
+
+// Record appends a single event, each record is written to the file before Record returns.
+// It is safe for concurrent use.
+func (j *Journal) Record(event models.JournalEvent, entry models.Entry, cause error) error {
+	record := models.JournalRecord{Time: time.Now().UTC(), Event: event, Entry: entry}
+	if cause != nil {
+		record.Error = cause.Error()
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal record: %w", err)
+	}
+	data = append(data, '\n')
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if _, err := j.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write journal record: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Read loads every record of the journal at path. A truncated last line, left behind by
+// a process killed while writing, is ignored.
+func Read(path string) ([]models.JournalRecord, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrFileDoesNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.JournalRecord, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record models.JournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			if !bytes.HasSuffix(data, []byte("\n")) && isLastLine(data, line) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode journal %s line %d: %w", path, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal %s: %w", path, err)
+	}
+
+	return records, nil
+}
+
+// isLastLine reports whether line, counted from 1, is the last line of data
+func isLastLine(data []byte, line int) bool {
+	return bytes.Count(data, []byte("\n"))+1 == line
+}
@@ -0,0 +1,157 @@
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestRecordRead_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	project := *models.NewEntry("sandbox-1", "Sandbox 1", models.EntryTypeProject)
+
+	journal, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	_ = journal.Record(models.JournalPlanned, project, nil)
+	_ = journal.Record(models.JournalStarted, project, nil)
+	_ = journal.Record(models.JournalFailed, project, errors.New("lien present"))
+	_ = journal.Close()
+
+	// Reopening appends instead of truncating
+	journal, err = Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen journal: %v", err)
+	}
+	_ = journal.Record(models.JournalSucceeded, project, nil)
+	_ = journal.Close()
+
+	records, err := Read(path)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(records))
+	}
+
+	if records[2].Event != models.JournalFailed || records[2].Error != "lien present" || records[2].Entry.Id != "sandbox-1" {
+		t.Errorf("Unexpected failed record %+v", records[2])
+	}
+
+	if records[3].Event != models.JournalSucceeded {
+		t.Errorf("Expected last record to be succeeded, got %s", records[3].Event)
+	}
+}
+
+func TestRecord_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = journal.Record(models.JournalStarted, *models.NewEntry("p", "P", models.EntryTypeProject), nil)
+		}()
+	}
+	wg.Wait()
+	_ = journal.Close()
+
+	records, err := Read(path)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+
+	if len(records) != 50 {
+		t.Errorf("Expected 50 records, got %d", len(records))
+	}
+}
+
+func TestRead_TruncatedLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	content := `{"time":"2024-01-01T00:00:00Z","event":"planned","entry":{"type":"project","id":"p1","name":"P1"}}` + "\n" +
+		`{"time":"2024-01-01T00:00:01Z","event":"sta`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write journal: %v", err)
+	}
+
+	records, err := Read(path)
+	if err != nil {
+		t.Fatalf("Expected truncated last line to be ignored, got %v", err)
+	}
+
+	if len(records) != 1 {
+		t.Errorf("Expected 1 record, got %d", len(records))
+	}
+}
+
+func TestOpen_RepairsTruncatedLastLine(t *testing.T) {
+	planned := `{"time":"2024-01-01T00:00:00Z","event":"planned","entry":{"type":"project","id":"p1","name":"P1"}}`
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{"cut off record", planned + "\n" + `{"time":"2024-01-01T00:00:01Z","event":"sta`, 1},
+		{"missing newline", planned + "\n" + planned, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "journal.jsonl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write journal: %v", err)
+			}
+
+			// Two resumed runs, each appending a record
+			for run := 0; run < 2; run++ {
+				runJournal, err := Open(path)
+				if err != nil {
+					t.Fatalf("Failed to open journal: %v", err)
+				}
+				if err := runJournal.Record(models.JournalSucceeded, *models.NewEntry("p1", "P1", models.EntryTypeProject), nil); err != nil {
+					t.Fatalf("Failed to record: %v", err)
+				}
+				_ = runJournal.Close()
+
+				records, err := Read(path)
+				if err != nil {
+					t.Fatalf("Expected the journal to stay readable after resume %d, got %v", run+1, err)
+				}
+				if len(records) != tt.expected+run+1 {
+					t.Errorf("Expected %d records after resume %d, got %d", tt.expected+run+1, run+1, len(records))
+				}
+			}
+		})
+	}
+}
+
+func TestRead_CorruptLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	if err := os.WriteFile(path, []byte("not json\n{}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write journal: %v", err)
+	}
+
+	if _, err := Read(path); err == nil {
+		t.Error("Expected error for a corrupt line, got nil")
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	if !errors.Is(err, apperrors.ErrFileDoesNotExist) {
+		t.Errorf("Expected ErrFileDoesNotExist, got %v", err)
+	}
+}
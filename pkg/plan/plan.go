@@ -0,0 +1,44 @@
+// Package plan reads and writes deletion plans as JSON files.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// Write stores the plan at path as indented JSON so it can be reviewed
+func Write(path string, plan *models.Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	data = append(data, '\n')
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Read loads and validates the plan stored at path
+func Read(path string) (*models.Plan, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrFileDoesNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to decode plan %s: %w", path, err)
+	}
+
+	if err := plan.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,78 @@
+package plan
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func newTestForest() *models.Forest {
+	child := models.NewNode(models.NewEntry("222", "Team", models.EntryTypeFolder), []models.Entry{*models.NewEntry("sandbox-1", "Sandbox 1", models.EntryTypeProject)})
+	root := models.NewNode(models.NewEntry("111", "Sandboxes", models.EntryTypeFolder), nil)
+	root.AddChild(child)
+
+	tree := models.NewTree()
+	tree.Root = root
+
+	return models.NewForest(tree)
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	forest := newTestForest()
+
+	if err := Write(path, models.NewPlan(forest, forest.PostOrderTraversal(), models.PlanDiscovery{MaxDepth: 3, Backend: "gcloud"}, time.Now())); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	loaded, err := Read(path)
+	if err != nil {
+		t.Fatalf("Failed to read plan: %v", err)
+	}
+
+	if err := loaded.CheckDrift(forest); err != nil {
+		t.Errorf("Expected no drift after a round trip, got %v", err)
+	}
+
+	if loaded.Discovery == nil || loaded.Discovery.MaxDepth != 3 || loaded.Discovery.Backend != "gcloud" {
+		t.Errorf("Expected the discovery settings to survive the round trip, got %+v", loaded.Discovery)
+	}
+
+	expected := []string{"sandbox-1", "222", "111"}
+	if len(loaded.Deletions) != len(expected) {
+		t.Fatalf("Expected %d deletions, got %d", len(expected), len(loaded.Deletions))
+	}
+	for i, entry := range loaded.Deletions {
+		if entry.Id != expected[i] {
+			t.Errorf("Deletion %d: expected %s, got %s", i, expected[i], entry.Id)
+		}
+	}
+
+	if loaded.Deletions[0].Type != models.EntryTypeProject || loaded.Deletions[1].Type != models.EntryTypeFolder {
+		t.Errorf("Expected entry types to survive the round trip, got %+v", loaded.Deletions)
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.json"))
+
+	if !errors.Is(err, apperrors.ErrFileDoesNotExist) {
+		t.Errorf("Expected ErrFileDoesNotExist, got %v", err)
+	}
+}
+
+func TestRead_InvalidPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "roots": [], "deletions": []}`), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := Read(path); err == nil {
+		t.Error("Expected error for a plan without roots, got nil")
+	}
+}
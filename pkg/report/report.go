@@ -0,0 +1,44 @@
+// Package report reads and writes the record of a deletion run as JSON files.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// Write stores the report at path as indented JSON
+func Write(path string, report *models.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	data = append(data, '\n')
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Read loads and validates the report stored at path
+func Read(path string) (*models.Report, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrFileDoesNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.Report{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %w", path, err)
+	}
+
+	if err := report.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+
+	return report, nil
+}
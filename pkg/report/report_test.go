@@ -0,0 +1,68 @@
+package report
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	written := models.NewReport(deletedAt, false)
+	written.Add(models.Result{
+		Entry:        *models.NewEntry("sandbox-1", "Sandbox 1", models.EntryTypeProject),
+		Status:       models.ResultDeleted,
+		Time:         deletedAt,
+		RemovedLiens: []models.Lien{{Name: "liens/p1-abc", Origin: "servicenetworking.googleapis.com"}},
+	})
+	written.Add(models.Result{
+		Entry:  *models.NewEntry("111", "Team", models.EntryTypeFolder),
+		Status: models.ResultFailed,
+		Error:  "folder is not empty",
+		Time:   deletedAt,
+	})
+	written.Finish(deletedAt.Add(time.Minute))
+
+	if err := Write(path, written); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	loaded, err := Read(path)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	if len(loaded.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(loaded.Results))
+	}
+
+	project := loaded.Results[0]
+	if project.Entry.Type != models.EntryTypeProject || project.Status != models.ResultDeleted || !project.Time.Equal(deletedAt) {
+		t.Errorf("Expected project result to survive the round trip, got %+v", project)
+	}
+	if len(project.RemovedLiens) != 1 || project.RemovedLiens[0].Name != "liens/p1-abc" {
+		t.Errorf("Expected removed liens to survive the round trip, got %+v", project.RemovedLiens)
+	}
+
+	if folder := loaded.Results[1]; folder.Status != models.ResultFailed || folder.Error != "folder is not empty" {
+		t.Errorf("Expected failed folder result, got %+v", folder)
+	}
+
+	if !loaded.FinishedAt.Equal(deletedAt.Add(time.Minute)) {
+		t.Errorf("Expected finish time to survive the round trip, got %v", loaded.FinishedAt)
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.json"))
+
+	if !errors.Is(err, apperrors.ErrFileDoesNotExist) {
+		t.Errorf("Expected ErrFileDoesNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+const day = 24 * time.Hour
+
+// dateLayout is the short form accepted by ParseDate next to RFC 3339
+const dateLayout = "2006-01-02"
+
+// ParseAge parses an age such as 30d, 2w or any time.ParseDuration value such as 36h
+func ParseAge(expression string) (time.Duration, error) {
+	expression = strings.TrimSpace(expression)
+
+	var age time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(expression, "d"):
+		age, err = parseDays(strings.TrimSuffix(expression, "d"), day)
+	case strings.HasSuffix(expression, "w"):
+		age, err = parseDays(strings.TrimSuffix(expression, "w"), 7*day)
+	default:
+		age, err = time.ParseDuration(expression)
+	}
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("%w: age %q", errors.ErrInvalidSelector, expression)
+	}
+
+	return age, nil
+}
+
+func parseDays(count string, unit time.Duration) (time.Duration, error) {
+	value, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(value) * unit, nil
+}
+
+// ParseDate parses a date such as 2024-01-31, read as midnight UTC, or an RFC 3339 timestamp
+func ParseDate(expression string) (time.Time, error) {
+	expression = strings.TrimSpace(expression)
+	if date, err := time.Parse(dateLayout, expression); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, expression); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: date %q", errors.ErrInvalidSelector, expression)
+}
+
+// CreatedBefore excludes every folder and project created at or after Cutoff. Entries without
+// a creation time are excluded as well, since their age cannot be proven.
+type CreatedBefore struct {
+	Cutoff time.Time
+	// Description names the requirement in reasons, e.g. "older than 30d"
+	Description string
+}
+
+// OlderThan returns the selector for entries created more than age before now
+func OlderThan(age time.Duration, expression string, now time.Time) CreatedBefore {
+	return CreatedBefore{Cutoff: now.Add(-age), Description: "older than " + expression}
+}
+
+func (s CreatedBefore) Decide(entry models.Entry) (models.Decision, bool) {
+	if entry.CreateTime.IsZero() {
+		return models.Decision{Excluded: true, Reason: "creation time unknown"}, true
+	}
+
+	created := entry.CreateTime.UTC().Format(dateLayout)
+	if !entry.CreateTime.Before(s.Cutoff) {
+		return models.Decision{Excluded: true, Reason: fmt.Sprintf("created %s, not %s", created, s.Description)}, true
+	}
+
+	return models.Decision{Reason: fmt.Sprintf("created %s, %s", created, s.Description)}, true
+}
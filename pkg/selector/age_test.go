@@ -0,0 +1,107 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   time.Duration
+	}{
+		{expression: "30d", expected: 30 * 24 * time.Hour},
+		{expression: "2w", expected: 14 * 24 * time.Hour},
+		{expression: "36h", expected: 36 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			age, err := ParseAge(tt.expression)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if age != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, age)
+			}
+		})
+	}
+}
+
+func TestParseAge_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "d", "thirty days", "-5d", "0d", "1.5d"} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := ParseAge(expression); !errors.Is(err, apperrors.ErrInvalidSelector) {
+				t.Errorf("Expected ErrInvalidSelector, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{expression: "2024-01-31", expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{expression: "2024-01-31T12:00:00+01:00", expected: time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			date, err := ParseDate(tt.expression)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !date.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, date)
+			}
+		})
+	}
+
+	if _, err := ParseDate("31/01/2024"); !errors.Is(err, apperrors.ErrInvalidSelector) {
+		t.Errorf("Expected ErrInvalidSelector, got %v", err)
+	}
+}
+
+func TestCreatedBefore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	selector := OlderThan(30*24*time.Hour, "30d", now)
+
+	entry := func(createTime time.Time) models.Entry {
+		e := models.NewEntry("p", "P", models.EntryTypeProject)
+		e.CreateTime = createTime
+		return *e
+	}
+
+	tests := []struct {
+		name     string
+		entry    models.Entry
+		excluded bool
+		reason   string
+	}{
+		{name: "old enough", entry: entry(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)), reason: "created 2024-01-15, older than 30d"},
+		{name: "too recent", entry: entry(time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)), excluded: true, reason: "created 2024-02-15, not older than 30d"},
+		{name: "unknown", entry: entry(time.Time{}), excluded: true, reason: "creation time unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, applies := selector.Decide(tt.entry)
+
+			if !applies {
+				t.Fatal("Expected selector to apply")
+			}
+
+			if decision.Excluded != tt.excluded || decision.Reason != tt.reason {
+				t.Errorf("Expected excluded=%v reason=%q, got %+v", tt.excluded, tt.reason, decision)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// Label matches the labels of a project against a single requirement:
+// key=value, key!=value, key (label present) or !key (label absent)
+type Label struct {
+	Key    string
+	Value  string
+	Negate bool
+	// Exists is set for the key and !key forms, which do not compare values
+	Exists bool
+}
+
+// ParseLabel parses a label requirement such as env=sandbox, keep!=true, team or !keep
+func ParseLabel(expression string) (Label, error) {
+	expression = strings.TrimSpace(expression)
+
+	var label Label
+	switch {
+	case strings.Contains(expression, "!="):
+		key, value, _ := strings.Cut(expression, "!=")
+		label = Label{Key: key, Value: value, Negate: true}
+	case strings.Contains(expression, "="):
+		key, value, _ := strings.Cut(expression, "=")
+		label = Label{Key: key, Value: value}
+	case strings.HasPrefix(expression, "!"):
+		label = Label{Key: strings.TrimPrefix(expression, "!"), Negate: true, Exists: true}
+	default:
+		label = Label{Key: expression, Exists: true}
+	}
+
+	label.Key = strings.TrimSpace(label.Key)
+	label.Value = strings.TrimSpace(label.Value)
+	if label.Key == "" || strings.ContainsAny(label.Key, "=!") {
+		return Label{}, fmt.Errorf("%w: label %q", errors.ErrInvalidSelector, expression)
+	}
+
+	return label, nil
+}
+
+// Matches reports whether the labels satisfy the requirement
+func (l Label) Matches(labels map[string]string) bool {
+	value, found := labels[l.Key]
+	if l.Exists {
+		return found != l.Negate
+	}
+	if l.Negate {
+		return !found || value != l.Value
+	}
+
+	return found && value == l.Value
+}
+
+func (l Label) String() string {
+	switch {
+	case l.Exists && l.Negate:
+		return "!" + l.Key
+	case l.Exists:
+		return l.Key
+	case l.Negate:
+		return l.Key + "!=" + l.Value
+	default:
+		return l.Key + "=" + l.Value
+	}
+}
+
+// IncludeLabel excludes every project whose labels do not match the requirement
+type IncludeLabel struct {
+	Label Label
+}
+
+func (s IncludeLabel) Decide(entry models.Entry) (models.Decision, bool) {
+	if entry.Type != models.EntryTypeProject {
+		return models.Decision{}, false
+	}
+	if !s.Label.Matches(entry.Labels) {
+		return models.Decision{Excluded: true, Reason: "does not match label " + s.Label.String()}, true
+	}
+
+	return models.Decision{Reason: "matches label " + s.Label.String()}, true
+}
+
+// ExcludeLabel excludes every project whose labels match the requirement
+type ExcludeLabel struct {
+	Label Label
+}
+
+func (s ExcludeLabel) Decide(entry models.Entry) (models.Decision, bool) {
+	if entry.Type != models.EntryTypeProject {
+		return models.Decision{}, false
+	}
+	if s.Label.Matches(entry.Labels) {
+		return models.Decision{Excluded: true, Reason: "matches label " + s.Label.String()}, true
+	}
+
+	return models.Decision{}, true
+}
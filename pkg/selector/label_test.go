@@ -0,0 +1,116 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func TestParseLabel(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   Label
+	}{
+		{expression: "env=sandbox", expected: Label{Key: "env", Value: "sandbox"}},
+		{expression: "keep!=true", expected: Label{Key: "keep", Value: "true", Negate: true}},
+		{expression: "team", expected: Label{Key: "team", Exists: true}},
+		{expression: "!keep", expected: Label{Key: "keep", Negate: true, Exists: true}},
+		{expression: " env = sandbox ", expected: Label{Key: "env", Value: "sandbox"}},
+		{expression: "owner=", expected: Label{Key: "owner"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			label, err := ParseLabel(tt.expression)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if label != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, label)
+			}
+		})
+	}
+}
+
+func TestParseLabel_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "=sandbox", "!=true", "!", "!env=sandbox"} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := ParseLabel(expression); !errors.Is(err, apperrors.ErrInvalidSelector) {
+				t.Errorf("Expected ErrInvalidSelector, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLabel_Matches(t *testing.T) {
+	labels := map[string]string{"env": "sandbox", "keep": "false"}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{expression: "env=sandbox", expected: true},
+		{expression: "env=prod", expected: false},
+		{expression: "keep!=true", expected: true},
+		{expression: "owner!=me", expected: true},
+		{expression: "env!=sandbox", expected: false},
+		{expression: "env", expected: true},
+		{expression: "owner", expected: false},
+		{expression: "!owner", expected: true},
+		{expression: "!env", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			label, err := ParseLabel(tt.expression)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if actual := label.Matches(labels); actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+
+			if label.String() != tt.expression {
+				t.Errorf("Expected %s to round trip, got %s", tt.expression, label.String())
+			}
+		})
+	}
+}
+
+func TestLabelSelectors(t *testing.T) {
+	include, _ := ParseLabel("env=sandbox")
+	exclude, _ := ParseLabel("keep=true")
+	set := Set{IncludeLabel{Label: include}, ExcludeLabel{Label: exclude}}
+
+	project := func(labels map[string]string) models.Entry {
+		entry := models.NewEntry("p", "P", models.EntryTypeProject)
+		entry.Labels = labels
+		return *entry
+	}
+
+	tests := []struct {
+		name     string
+		entry    models.Entry
+		excluded bool
+		reason   string
+	}{
+		{name: "matching project", entry: project(map[string]string{"env": "sandbox"}), reason: "matches label env=sandbox"},
+		{name: "other environment", entry: project(map[string]string{"env": "prod"}), excluded: true, reason: "does not match label env=sandbox"},
+		{name: "kept project", entry: project(map[string]string{"env": "sandbox", "keep": "true"}), excluded: true, reason: "matches label keep=true"},
+		{name: "folder", entry: *models.NewEntry("f", "F", models.EntryTypeFolder)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := set.Decide(tt.entry)
+
+			if decision.Excluded != tt.excluded || decision.Reason != tt.reason {
+				t.Errorf("Expected excluded=%v reason=%q, got %+v", tt.excluded, tt.reason, decision)
+			}
+		})
+	}
+}
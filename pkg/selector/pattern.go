@@ -0,0 +1,113 @@
+package selector
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+// regexPrefix marks a pattern as a regular expression, every other pattern is a glob
+const regexPrefix = "re:"
+
+// Pattern is a glob such as ci-pr-* or a regular expression such as re:^ci-pr-[0-9]+$
+type Pattern struct {
+	expression string
+	regex      *regexp.Regexp
+}
+
+// ParsePattern compiles a glob or, when prefixed with re:, a regular expression
+func ParsePattern(expression string) (Pattern, error) {
+	if expression == "" {
+		return Pattern{}, fmt.Errorf("%w: empty pattern", errors.ErrInvalidSelector)
+	}
+
+	if source, isRegex := strings.CutPrefix(expression, regexPrefix); isRegex {
+		regex, err := regexp.Compile(source)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("%w: pattern %q: %v", errors.ErrInvalidSelector, expression, err)
+		}
+		return Pattern{expression: expression, regex: regex}, nil
+	}
+
+	if _, err := path.Match(expression, ""); err != nil {
+		return Pattern{}, fmt.Errorf("%w: pattern %q: %v", errors.ErrInvalidSelector, expression, err)
+	}
+
+	return Pattern{expression: expression}, nil
+}
+
+// MatchString reports whether value matches the pattern, globs have to match the whole value
+func (p Pattern) MatchString(value string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(value)
+	}
+	matched, _ := path.Match(p.expression, value)
+
+	return matched
+}
+
+func (p Pattern) String() string {
+	return p.expression
+}
+
+// matchEntry returns a reason when the name or id of the entry, or of one of its ancestors, matches
+// one of the patterns. Ancestors are checked from the nearest one up, so a folder match covers its subtree.
+func matchEntry(patterns []Pattern, entry models.Entry) (string, bool) {
+	for _, pattern := range patterns {
+		if pattern.MatchString(entry.Name) || pattern.MatchString(entry.Id) {
+			return "matches " + pattern.String(), true
+		}
+	}
+
+	for i := len(entry.Ancestry) - 1; i >= 0; i-- {
+		ancestor := entry.Ancestry[i]
+		for _, pattern := range patterns {
+			if pattern.MatchString(ancestor.Name) || pattern.MatchString(ancestor.Id) {
+				return fmt.Sprintf("inside %s (%s) which matches %s", ancestor.Name, ancestor.Id, pattern.String()), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Match excludes every folder and project that does not match any of the patterns,
+// neither itself nor through one of its ancestors
+type Match struct {
+	Patterns []Pattern
+}
+
+func (s Match) Decide(entry models.Entry) (models.Decision, bool) {
+	if reason, matched := matchEntry(s.Patterns, entry); matched {
+		return models.Decision{Reason: reason}, true
+	}
+
+	return models.Decision{Excluded: true, Reason: "does not match " + joinPatterns(s.Patterns)}, true
+}
+
+// Exclude excludes every folder and project that matches one of the patterns, itself or
+// through one of its ancestors, so an excluded folder prunes its whole subtree
+type Exclude struct {
+	Patterns []Pattern
+}
+
+func (s Exclude) Decide(entry models.Entry) (models.Decision, bool) {
+	if reason, matched := matchEntry(s.Patterns, entry); matched {
+		return models.Decision{Excluded: true, Reason: reason}, true
+	}
+
+	return models.Decision{}, true
+}
+
+func joinPatterns(patterns []Pattern) string {
+	expressions := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		expressions = append(expressions, pattern.String())
+	}
+
+	return strings.Join(expressions, ", ")
+}
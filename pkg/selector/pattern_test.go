@@ -0,0 +1,137 @@
+package selector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func mustParsePatterns(t *testing.T, expressions ...string) []Pattern {
+	t.Helper()
+	patterns := make([]Pattern, 0, len(expressions))
+	for _, expression := range expressions {
+		pattern, err := ParsePattern(expression)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", expression, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
+
+func TestPattern_MatchString(t *testing.T) {
+	tests := []struct {
+		expression string
+		value      string
+		expected   bool
+	}{
+		{expression: "ci-pr-*", value: "ci-pr-1234", expected: true},
+		{expression: "ci-pr-*", value: "my-ci-pr-1234", expected: false},
+		{expression: "ci-pr-?", value: "ci-pr-1", expected: true},
+		{expression: "re:^ci-pr-[0-9]+$", value: "ci-pr-1234", expected: true},
+		{expression: "re:^ci-pr-[0-9]+$", value: "ci-pr-abc", expected: false},
+		{expression: "re:sandbox", value: "team-sandbox-1", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression+" "+tt.value, func(t *testing.T) {
+			pattern := mustParsePatterns(t, tt.expression)[0]
+
+			if actual := pattern.MatchString(tt.value); actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParsePattern_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "ci-[", "re:ci-("} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := ParsePattern(expression); !errors.Is(err, apperrors.ErrInvalidSelector) {
+				t.Errorf("Expected ErrInvalidSelector, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPatternSelectors(t *testing.T) {
+	shared := models.Ancestor{Type: models.EntryTypeFolder, Id: "100", Name: "Shared"}
+	ciFolder := models.Ancestor{Type: models.EntryTypeFolder, Id: "200", Name: "ci-pr-7"}
+
+	entry := func(id string, entryType models.EntryType, ancestry ...models.Ancestor) models.Entry {
+		e := models.NewEntry(id, id, entryType)
+		e.Ancestry = ancestry
+		return *e
+	}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		entry    models.Entry
+		excluded bool
+		reason   string
+	}{
+		{
+			name:     "matching project",
+			selector: Match{Patterns: mustParsePatterns(t, "ci-pr-*")},
+			entry:    entry("ci-pr-1", models.EntryTypeProject, shared),
+			reason:   "matches ci-pr-*",
+		},
+		{
+			name:     "project in matching folder",
+			selector: Match{Patterns: mustParsePatterns(t, "ci-pr-*")},
+			entry:    entry("build-cache", models.EntryTypeProject, shared, ciFolder),
+			reason:   "inside ci-pr-7 (200) which matches ci-pr-*",
+		},
+		{
+			name:     "project that does not match",
+			selector: Match{Patterns: mustParsePatterns(t, "ci-pr-*", "re:^tmp-")},
+			entry:    entry("billing", models.EntryTypeProject, shared),
+			excluded: true,
+			reason:   "does not match ci-pr-*, re:^tmp-",
+		},
+		{
+			name:     "folder that does not match",
+			selector: Match{Patterns: mustParsePatterns(t, "ci-pr-*")},
+			entry:    entry("Shared", models.EntryTypeFolder),
+			excluded: true,
+			reason:   "does not match ci-pr-*",
+		},
+		{
+			name:     "excluded by id",
+			selector: Exclude{Patterns: mustParsePatterns(t, "re:^shared-")},
+			entry:    entry("shared-dns", models.EntryTypeProject),
+			excluded: true,
+			reason:   "matches re:^shared-",
+		},
+		{
+			name:     "pruned by folder",
+			selector: Exclude{Patterns: mustParsePatterns(t, "Shared")},
+			entry:    entry("ci-pr-1", models.EntryTypeProject, shared, ciFolder),
+			excluded: true,
+			reason:   "inside Shared (100) which matches Shared",
+		},
+		{
+			name:     "not excluded",
+			selector: Exclude{Patterns: mustParsePatterns(t, "Shared")},
+			entry:    entry("ci-pr-1", models.EntryTypeProject, ciFolder),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, applies := tt.selector.Decide(tt.entry)
+
+			if !applies {
+				t.Fatal("Expected selector to apply")
+			}
+
+			if decision.Excluded != tt.excluded || decision.Reason != tt.reason {
+				t.Errorf("Expected excluded=%v reason=%q, got %+v", tt.excluded, tt.reason, decision)
+			}
+		})
+	}
+}
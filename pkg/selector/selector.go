@@ -0,0 +1,35 @@
+// Package selector decides which folders and projects take part in a deletion.
+package selector
+
+import (
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+)
+
+// Selector decides about a single entry
+type Selector interface {
+	// Decide returns the decision for the entry and whether the selector applies to it at all
+	Decide(entry models.Entry) (models.Decision, bool)
+}
+
+// Set combines selectors. An entry is excluded as soon as one selector excludes it,
+// otherwise the reason of the first selector that selected it is kept.
+type Set []Selector
+
+// Decide returns the combined decision of every selector of the set
+func (s Set) Decide(entry models.Entry) models.Decision {
+	result := models.Decision{}
+	for _, selector := range s {
+		decision, applies := selector.Decide(entry)
+		if !applies {
+			continue
+		}
+		if decision.Excluded {
+			return decision
+		}
+		if result.Reason == "" {
+			result.Reason = decision.Reason
+		}
+	}
+
+	return result
+}
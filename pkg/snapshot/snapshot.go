@@ -0,0 +1,68 @@
+// Package snapshot reads and writes archived resource trees as JSON or YAML files.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	"github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Write stores the snapshot at path, as YAML when the extension is .yaml or .yml and as JSON otherwise
+func Write(path string, snapshot *models.Snapshot) error {
+	var data []byte
+	var err error
+
+	if isYAML(path) {
+		data, err = yaml.Marshal(snapshot)
+	} else {
+		data, err = json.MarshalIndent(snapshot, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Read loads and validates the snapshot stored at path
+func Read(path string) (*models.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrFileDoesNotExist, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.Snapshot{}
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, snapshot)
+	} else {
+		err = json.Unmarshal(data, snapshot)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+
+	if err := snapshot.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cupsadarius/gcp_resource_cleaner/models"
+	apperrors "github.com/cupsadarius/gcp_resource_cleaner/pkg/errors"
+)
+
+func newTestForest() *models.Forest {
+	project := models.NewEntry("sandbox-1", "Sandbox 1", models.EntryTypeProject)
+	project.Number = "123456"
+	project.LifecycleState = "ACTIVE"
+	project.CreateTime = time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)
+	project.Labels = map[string]string{"env": "sandbox"}
+
+	child := models.NewNode(models.NewEntry("222", "Team", models.EntryTypeFolder), []models.Entry{*project})
+	rootEntry := models.NewEntry("111", "Sandboxes", models.EntryTypeFolder)
+	rootEntry.Ancestry = []models.Ancestor{{Type: models.EntryTypeOrganization, Id: "100", Name: "Acme Org"}}
+	root := models.NewNode(rootEntry, nil)
+	root.AddChild(child)
+
+	tree := models.NewTree()
+	tree.Root = root
+
+	return models.NewForest(tree)
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	discoveredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, name := range []string{"tree.json", "tree.yaml", "tree.yml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+
+			if err := Write(path, models.NewSnapshot(newTestForest(), discoveredAt)); err != nil {
+				t.Fatalf("Failed to write snapshot: %v", err)
+			}
+
+			loaded, err := Read(path)
+			if err != nil {
+				t.Fatalf("Failed to read snapshot: %v", err)
+			}
+
+			if loaded.Version != models.SnapshotVersion {
+				t.Errorf("Expected version %d, got %d", models.SnapshotVersion, loaded.Version)
+			}
+
+			if !loaded.DiscoveredAt.Equal(discoveredAt) {
+				t.Errorf("Expected discovery time %v, got %v", discoveredAt, loaded.DiscoveredAt)
+			}
+
+			forest := loaded.Forest()
+			if len(forest.Trees) != 1 {
+				t.Fatalf("Expected 1 tree, got %d", len(forest.Trees))
+			}
+
+			root := forest.Trees[0].Root
+			if root.Current.Type != models.EntryTypeFolder || root.Current.Ancestry[0].Type != models.EntryTypeOrganization {
+				t.Errorf("Expected entry types to survive the round trip, got %+v", root.Current)
+			}
+
+			child := root.Children[0]
+			if child.Parent != root {
+				t.Error("Expected parent pointers to be restored")
+			}
+
+			project := child.Values[0]
+			if project.Number != "123456" || project.Labels["env"] != "sandbox" || !project.CreateTime.Equal(time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("Expected project metadata to survive the round trip, got %+v", project)
+			}
+
+			if project.Path() != "Acme Org/Sandboxes/Team/Sandbox 1" {
+				t.Errorf("Expected ancestry to survive the round trip, got %s", project.Path())
+			}
+		})
+	}
+}
+
+func TestRead_MissingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.json"))
+
+	if !errors.Is(err, apperrors.ErrFileDoesNotExist) {
+		t.Errorf("Expected ErrFileDoesNotExist, got %v", err)
+	}
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "trees": []}`), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := Read(path); err == nil {
+		t.Error("Expected error for an unsupported version, got nil")
+	}
+}
+
+func TestRead_InvalidContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.json")
+	if err := os.WriteFile(path, []byte(`not json`), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := Read(path); err == nil {
+		t.Error("Expected error for invalid content, got nil")
+	}
+}